@@ -29,6 +29,27 @@ func main() {
 		application.HTTPServer.MustRun()
 	}()
 
+	// Reloading labels and clusters of addresses by SIGHUP
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	go func() {
+		for range reload {
+			// Stores are reloaded independently, so error in one of them doesn't block another
+			if err := application.Labels.Reload(); err != nil {
+				log.Error("Reloading labels error: ", sl.Err(err))
+			} else {
+				log.Info("Labels reloaded", "count", application.Labels.Len())
+			}
+
+			if err := application.Clusters.Reload(); err != nil {
+				log.Error("Reloading clusters error: ", sl.Err(err))
+			} else {
+				log.Info("Clusters reloaded", "entities", len(application.Clusters.GetClusters()))
+			}
+		}
+	}()
+
 	// Graceful shutdown
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
@@ -37,6 +58,11 @@ func main() {
 
 	log.Info("Starting graceful shutdown")
 
+	application.Follower.Stop()
+	application.Hub.Stop()
+	application.Stream.Stop()
+	application.Alerter.Stop()
+
 	err := application.HTTPServer.Stop()
 	if err != nil {
 		log.Error("Shutting down error: ", sl.Err(err))
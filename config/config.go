@@ -16,19 +16,42 @@ const (
 
 type (
 	Config struct {
-		App  `yaml:"app"`
-		API  `yaml:"api"`
-		HTTP `yaml:"http"`
-		Log  `yaml:"logger"`
+		App    `yaml:"app"`
+		API    `yaml:"api"`
+		HTTP   `yaml:"http"`
+		Alerts `yaml:"alerts"`
+		Log    `yaml:"logger"`
 	}
 
 	App struct {
 		Name                    string `env:"APP_NAME"            env-default:"biggest-change" yaml:"name"`
 		Version                 string `env:"APP_VERSION"         env-default:"1.0.0"          yaml:"version"`
 		CountOfBlocks           uint   `env:"APP_COUNT_OF_BLOCKS" env-default:"100"            yaml:"countOfBlocks"`
+		MaxCountOfBlocks        uint   `env:"APP_MAX_BLOCKS"      env-default:"10000"          yaml:"maxCountOfBlocks"`
 		MaxGoroutines           int    `env:"APP_MAX_GOROUTINES"  env-default:"50"             yaml:"maxGoroutines"`
 		AverageAddressesInBlock int    `env:"APP_AVG_ADDRS"       env-default:"200"            yaml:"averageAddressesInBlock"`
 		CacheSize               int    `env:"APP_CACHE_SIZE"      env-default:"100"            yaml:"cacheSize"`
+		HeaderCacheSize         int    `env:"APP_HEADER_CACHE"    env-default:"1000"           yaml:"headerCacheSize"`
+		TopLimit                uint   `env:"APP_TOP_LIMIT"       env-default:"10"             yaml:"topLimit"`
+		MaxTopLimit             uint   `env:"APP_MAX_TOP_LIMIT"   env-default:"100"            yaml:"maxTopLimit"`
+		Withdrawals             bool   `env:"APP_WITHDRAWALS"     env-default:"true"           yaml:"withdrawals"`
+		ConfirmationDepth       uint   `env:"APP_CONFIRMATIONS"   env-default:"0"              yaml:"confirmationDepth"`
+		Anchor                  string `env:"APP_ANCHOR"          env-default:"latest"         yaml:"anchor"`
+		ExcludeFile             string `env:"APP_EXCLUDE_FILE"    env-default:""               yaml:"excludeFile"`
+		LabelsFile              string `env:"APP_LABELS_FILE"     env-default:""               yaml:"labelsFile"`
+		ClustersFile            string `env:"APP_CLUSTERS_FILE"   env-default:""               yaml:"clustersFile"`
+		RelativeCandidates      int    `env:"APP_REL_CANDIDATES"  env-default:"100"            yaml:"relativeCandidates"`
+
+		// Minimal balance in ether or in whole tokens for relative metric
+		RelativeMinBalance float64 `env:"APP_REL_MIN_BALANCE" env-default:"1" yaml:"relativeMinBalance"`
+
+		// Interval of polling head to follow default window, follower is disabled if it's zero
+		FollowInterval time.Duration `env:"APP_FOLLOW_INTERVAL" env-default:"12s" yaml:"followInterval"`
+		// Age of precomputed result after which it's marked as stale, zero means three follow intervals
+		StaleAfter time.Duration `env:"APP_STALE_AFTER" env-default:"0s" yaml:"staleAfter"`
+
+		// Addresses which are never ranked, addresses from ExcludeFile are added to them
+		Exclude []string `yaml:"exclude"`
 	}
 
 	API struct {
@@ -38,11 +61,49 @@ type (
 		Timeout            time.Duration `env:"API_TIMEOUT"              env-default:"5s"    yaml:"timeout"`
 		MaxRetries         int           `env:"API_MAX_RETRIES"          env-default:"5"     yaml:"maxRetries"`
 		TimeBetweenRetries time.Duration `env:"API_TIME_BETWEEN_RETRIES" env-default:"500ms" yaml:"timeBetweenRetries"`
+		Tracing            bool          `env:"API_TRACING"              env-default:"false" yaml:"tracing"`
+		LogsBlockRange     uint64        `env:"API_LOGS_BLOCK_RANGE"     env-default:"1000"  yaml:"logsBlockRange"`
 	}
 
+	// Admin api is disabled if AdminToken is empty
+	// Websocket client is disconnected if it doesn't answer pings during WSPongWait
+	// Distinct websocket subscriptions are computed on every block by WSWorkers, so their count is limited
+	// SSEHistory is count of the latest events which are kept for resumption of stream
 	HTTP struct {
-		Port    string        `env:"HTTP_PORT"    env-default:":8080" yaml:"port"`
-		Timeout time.Duration `env:"HTTP_TIMEOUT" env-default:"5s"    yaml:"timeout"`
+		Port               string        `env:"HTTP_PORT"                 env-default:":8080" yaml:"port"`
+		Timeout            time.Duration `env:"HTTP_TIMEOUT"              env-default:"5s"    yaml:"timeout"`
+		AdminToken         string        `env:"HTTP_ADMIN_TOKEN"          env-default:""      yaml:"adminToken"`
+		WSMaxClients       int           `env:"HTTP_WS_MAX_CLIENTS"       env-default:"1000"  yaml:"wsMaxClients"`
+		WSMaxSubscriptions int           `env:"HTTP_WS_MAX_SUBSCRIPTIONS" env-default:"10"    yaml:"wsMaxSubscriptions"`
+		WSPongWait         time.Duration `env:"HTTP_WS_PONG_WAIT"         env-default:"60s"   yaml:"wsPongWait"`
+		WSMaxQueries       int           `env:"HTTP_WS_MAX_QUERIES"       env-default:"100"   yaml:"wsMaxQueries"`
+		WSMaxBlocks        uint          `env:"HTTP_WS_MAX_BLOCKS"        env-default:"1000"  yaml:"wsMaxBlocks"`
+		WSWorkers          int           `env:"HTTP_WS_WORKERS"           env-default:"4"     yaml:"wsWorkers"`
+		WSComputeTimeout   time.Duration `env:"HTTP_WS_COMPUTE_TIMEOUT"   env-default:"10s"   yaml:"wsComputeTimeout"`
+		SSEHistory         int           `env:"HTTP_SSE_HISTORY"          env-default:"100"   yaml:"sseHistory"`
+	}
+
+	// Alerts are signed by Secret if it's set, undelivered alerts are appended to DeadLetterFile
+	Alerts struct {
+		Secret         string        `env:"ALERTS_SECRET"           env-default:""   yaml:"secret"`
+		MaxRetries     int           `env:"ALERTS_MAX_RETRIES"      env-default:"5"  yaml:"maxRetries"`
+		Backoff        time.Duration `env:"ALERTS_BACKOFF"          env-default:"1s" yaml:"backoff"`
+		Timeout        time.Duration `env:"ALERTS_TIMEOUT"          env-default:"5s" yaml:"timeout"`
+		DeadLetterFile string        `env:"ALERTS_DEAD_LETTER_FILE" env-default:""   yaml:"deadLetterFile"`
+
+		// Rules which can't be deleted through admin api
+		Rules []AlertRule `yaml:"rules"`
+	}
+
+	// Threshold is set in wei as decimal or hex number, direction is in, out or any
+	AlertRule struct {
+		ID            string `yaml:"id"`
+		Threshold     string `yaml:"threshold"`
+		Direction     string `yaml:"direction"`
+		CountOfBlocks uint   `yaml:"countOfBlocks"`
+		Address       string `yaml:"address"`
+		Category      string `yaml:"category"`
+		Webhook       string `yaml:"webhook"`
 	}
 
 	Log struct {
@@ -74,6 +135,10 @@ func MustLoadPath(configPath, envPath string) *Config {
 		panic("cannot read config: " + err.Error())
 	}
 
+	if err := loadExclude(&cfg.App); err != nil {
+		panic("cannot load exclude list: " + err.Error())
+	}
+
 	return &cfg
 }
 
@@ -17,6 +17,11 @@ app:
   maxGoroutines: 50
   averageAddressesInBlock: 200
   cacheSize: 100
+  headerCacheSize: 1000
+  topLimit: 10
+  withdrawals: true
+  confirmationDepth: 12
+  anchor: "finalized"
 
 api:
   url: test-URL
@@ -25,6 +30,7 @@ api:
   timeout: 5s
   maxRetries: 5
   timeBetweenRetries: 500ms
+  tracing: false
 
 http:
   port: ":8080"
@@ -41,11 +47,17 @@ APP_COUNT_OF_BLOCKS=100
 APP_MAX_GOROUTINES=50
 APP_AVG_ADDRS=200
 APP_CACHE_SIZE=100
+APP_HEADER_CACHE=1000
+APP_TOP_LIMIT=10
+APP_WITHDRAWALS=true
+APP_CONFIRMATIONS=12
+APP_ANCHOR=finalized
 API_URL=test-URL
 API_RPS=60
 API_TIME_WINDOW_RPS=1s
 API_TIMEOUT=5s
 API_TIME_BETWEEN_RETRIES=500ms
+API_TRACING=false
 HTTP_PORT=:8080
 HTTP_TIMEOUT=5s
 LOG_LEVEL=info`
@@ -97,9 +109,19 @@ var testsMustLoadPath = []struct {
 				Name:                    "biggest-change",
 				Version:                 "1.0.0",
 				CountOfBlocks:           100,
+				MaxCountOfBlocks:        10000,
 				MaxGoroutines:           50,
 				AverageAddressesInBlock: 200,
 				CacheSize:               100,
+				HeaderCacheSize:         1000,
+				TopLimit:                10,
+				MaxTopLimit:             100,
+				Withdrawals:             true,
+				Anchor:                  "latest",
+				RelativeCandidates:      100,
+				RelativeMinBalance:      1,
+				FollowInterval:          12 * time.Second,
+				StaleAfter:              0,
 			},
 			API: API{
 				URL:                "",
@@ -108,10 +130,24 @@ var testsMustLoadPath = []struct {
 				Timeout:            5 * time.Second,
 				MaxRetries:         5,
 				TimeBetweenRetries: 500 * time.Millisecond,
+				LogsBlockRange:     1000,
 			},
 			HTTP: HTTP{
-				Port:    ":8080",
-				Timeout: 5 * time.Second,
+				Port:               ":8080",
+				Timeout:            5 * time.Second,
+				WSMaxClients:       1000,
+				WSMaxSubscriptions: 10,
+				WSPongWait:         60 * time.Second,
+				WSMaxQueries:       100,
+				WSMaxBlocks:        1000,
+				WSWorkers:          4,
+				WSComputeTimeout:   10 * time.Second,
+				SSEHistory:         100,
+			},
+			Alerts: Alerts{
+				MaxRetries: 5,
+				Backoff:    time.Second,
+				Timeout:    5 * time.Second,
 			},
 			Log: Log{
 				Level: "debug",
@@ -127,9 +163,20 @@ var testsMustLoadPath = []struct {
 				Name:                    "test-app",
 				Version:                 "1.0.0",
 				CountOfBlocks:           100,
+				MaxCountOfBlocks:        10000,
 				MaxGoroutines:           50,
 				AverageAddressesInBlock: 200,
 				CacheSize:               100,
+				HeaderCacheSize:         1000,
+				TopLimit:                10,
+				MaxTopLimit:             100,
+				Withdrawals:             true,
+				ConfirmationDepth:       12,
+				Anchor:                  "finalized",
+				RelativeCandidates:      100,
+				RelativeMinBalance:      1,
+				FollowInterval:          12 * time.Second,
+				StaleAfter:              0,
 			},
 			API: API{
 				URL:                "test-URL",
@@ -138,10 +185,24 @@ var testsMustLoadPath = []struct {
 				Timeout:            5 * time.Second,
 				MaxRetries:         5,
 				TimeBetweenRetries: 500 * time.Millisecond,
+				LogsBlockRange:     1000,
 			},
 			HTTP: HTTP{
-				Port:    ":8080",
-				Timeout: 5 * time.Second,
+				Port:               ":8080",
+				Timeout:            5 * time.Second,
+				WSMaxClients:       1000,
+				WSMaxSubscriptions: 10,
+				WSPongWait:         60 * time.Second,
+				WSMaxQueries:       100,
+				WSMaxBlocks:        1000,
+				WSWorkers:          4,
+				WSComputeTimeout:   10 * time.Second,
+				SSEHistory:         100,
+			},
+			Alerts: Alerts{
+				MaxRetries: 5,
+				Backoff:    time.Second,
+				Timeout:    5 * time.Second,
 			},
 			Log: Log{
 				Level: "info",
@@ -157,9 +218,20 @@ var testsMustLoadPath = []struct {
 				Name:                    "else-name",
 				Version:                 "1.0.0",
 				CountOfBlocks:           100,
+				MaxCountOfBlocks:        10000,
 				MaxGoroutines:           50,
 				AverageAddressesInBlock: 200,
 				CacheSize:               100,
+				HeaderCacheSize:         1000,
+				TopLimit:                10,
+				MaxTopLimit:             100,
+				Withdrawals:             true,
+				ConfirmationDepth:       12,
+				Anchor:                  "finalized",
+				RelativeCandidates:      100,
+				RelativeMinBalance:      1,
+				FollowInterval:          12 * time.Second,
+				StaleAfter:              0,
 			},
 			API: API{
 				URL:                "test-URL",
@@ -168,10 +240,24 @@ var testsMustLoadPath = []struct {
 				Timeout:            5 * time.Second,
 				MaxRetries:         5,
 				TimeBetweenRetries: 500 * time.Millisecond,
+				LogsBlockRange:     1000,
 			},
 			HTTP: HTTP{
-				Port:    ":8080",
-				Timeout: 5 * time.Second,
+				Port:               ":8080",
+				Timeout:            5 * time.Second,
+				WSMaxClients:       1000,
+				WSMaxSubscriptions: 10,
+				WSPongWait:         60 * time.Second,
+				WSMaxQueries:       100,
+				WSMaxBlocks:        1000,
+				WSWorkers:          4,
+				WSComputeTimeout:   10 * time.Second,
+				SSEHistory:         100,
+			},
+			Alerts: Alerts{
+				MaxRetries: 5,
+				Backoff:    time.Second,
+				Timeout:    5 * time.Second,
 			},
 			Log: Log{
 				Level: "info",
@@ -187,9 +273,20 @@ var testsMustLoadPath = []struct {
 				Name:                    "else-name",
 				Version:                 "1.0.0",
 				CountOfBlocks:           100,
+				MaxCountOfBlocks:        10000,
 				MaxGoroutines:           50,
 				AverageAddressesInBlock: 200,
 				CacheSize:               100,
+				HeaderCacheSize:         1000,
+				TopLimit:                10,
+				MaxTopLimit:             100,
+				Withdrawals:             true,
+				ConfirmationDepth:       12,
+				Anchor:                  "finalized",
+				RelativeCandidates:      100,
+				RelativeMinBalance:      1,
+				FollowInterval:          12 * time.Second,
+				StaleAfter:              0,
 			},
 			API: API{
 				URL:                "test-URL",
@@ -198,10 +295,24 @@ var testsMustLoadPath = []struct {
 				Timeout:            5 * time.Second,
 				MaxRetries:         5,
 				TimeBetweenRetries: 500 * time.Millisecond,
+				LogsBlockRange:     1000,
 			},
 			HTTP: HTTP{
-				Port:    ":8080",
-				Timeout: 5 * time.Second,
+				Port:               ":8080",
+				Timeout:            5 * time.Second,
+				WSMaxClients:       1000,
+				WSMaxSubscriptions: 10,
+				WSPongWait:         60 * time.Second,
+				WSMaxQueries:       100,
+				WSMaxBlocks:        1000,
+				WSWorkers:          4,
+				WSComputeTimeout:   10 * time.Second,
+				SSEHistory:         100,
+			},
+			Alerts: Alerts{
+				MaxRetries: 5,
+				Backoff:    time.Second,
+				Timeout:    5 * time.Second,
 			},
 			Log: Log{
 				Level: "info",
@@ -220,6 +331,59 @@ func Test_MustLoadPath_NonExistentPath(t *testing.T) {
 	MustLoadPath("non_existent_config.yml", "non_existent_env.env")
 }
 
+func Test_MustLoadPath_Exclude(t *testing.T) {
+	// temp file with excluded addresses
+	tempFileExclude, err := os.CreateTemp("", "exclude-*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tempFileExclude.Name())
+
+	_, err = tempFileExclude.WriteString("# hot wallets\n\n0xBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// temp config.yml file
+	tempFileConfig, err := os.CreateTemp("", "config-*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tempFileConfig.Name())
+
+	_, err = tempFileConfig.WriteString("app:\n  excludeFile: " + tempFileExclude.Name() +
+		"\n  exclude: [\"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\"]\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := MustLoadPath(tempFileConfig.Name(), "non_existent_env.env")
+	assert.Equal(t, []string{
+		"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		"0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+	}, config.App.Exclude)
+}
+
+func Test_MustLoadPath_InvalidExclude(t *testing.T) {
+	tempFileConfig, err := os.CreateTemp("", "config-*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tempFileConfig.Name())
+
+	_, err = tempFileConfig.WriteString("app:\n  exclude: [\"0x123\"]\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("The code did not panic")
+		}
+	}()
+
+	MustLoadPath(tempFileConfig.Name(), "non_existent_env.env")
+}
+
 func Test_fetchConfigPath(t *testing.T) {
 	for _, test := range testsFetchConfigPath {
 		t.Run(test.name, func(t *testing.T) {
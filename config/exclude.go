@@ -0,0 +1,56 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Adding addresses from exclude file to exclude list and validating them.
+func loadExclude(app *App) error {
+	if app.ExcludeFile != "" {
+		addrs, err := readAddresses(app.ExcludeFile)
+		if err != nil {
+			return err
+		}
+
+		app.Exclude = append(app.Exclude, addrs...)
+	}
+
+	for i, addr := range app.Exclude {
+		res, err := entity.NormalizeAddress(addr)
+		if err != nil {
+			return err
+		}
+
+		app.Exclude[i] = res
+	}
+
+	return nil
+}
+
+// Reading addresses from file, one address in line.
+// Empty lines and lines started with # are skipped.
+func readAddresses(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var res []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		res = append(res, line)
+	}
+
+	return res, scanner.Err()
+}
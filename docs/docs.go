@@ -15,9 +15,321 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/addresses/{address}/changes": {
+            "get": {
+                "description": "Получение изменений баланса address в каждом блоке окна и общего изменения\nОкно задаётся так же, как в /get_biggest_change\nБлоки, в которых баланс адреса не изменился, пропускаются",
+                "tags": [
+                    "StatsOfChanging"
+                ],
+                "summary": "Получение истории изменений адреса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Адрес",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество последних блоков",
+                        "name": "count_of_blocks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Первый блок",
+                        "name": "from_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Последний блок",
+                        "name": "to_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Адрес ERC-20 токена, изменения считаются в базовых единицах токена",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Блок, на котором заканчивается окно: latest, safe или finalized",
+                        "name": "anchor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало окна по времени блоков в формате RFC 3339",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Длительность окна по времени блоков, например 1h30m",
+                        "name": "duration",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "История получена",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AddressChanges"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в запросе"
+                    },
+                    "500": {
+                        "description": "Не удалось выполнить запрос"
+                    },
+                    "504": {
+                        "description": "Таймаут запроса"
+                    }
+                }
+            }
+        },
+        "/admin/alerts": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Получение правил из конфига и правил, заданных через api",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Получение правил оповещений",
+                "responses": {
+                    "200": {
+                        "description": "Правила получены",
+                        "schema": {
+                            "$ref": "#/definitions/v1.alertRulesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный токен"
+                    }
+                }
+            }
+        },
+        "/admin/alerts/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Задание правила, правило с тем же id заменяется\nthreshold - порог изменения в wei (десятичное или шестнадцатеричное число)\ndirection - направление изменения: in, out или any, по умолчанию any",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Задание правила оповещения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор правила",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Правило",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.setAlertRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Правило задано"
+                    },
+                    "400": {
+                        "description": "Ошибка в запросе"
+                    },
+                    "401": {
+                        "description": "Неверный токен"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Удаление правила, заданного через api, правило из конфига с тем же id восстанавливается",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Удаление правила оповещения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор правила",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Правило удалено"
+                    },
+                    "401": {
+                        "description": "Неверный токен"
+                    },
+                    "404": {
+                        "description": "Правило не найдено"
+                    }
+                }
+            }
+        },
+        "/admin/clusters": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Получение всех сущностей и их адресов",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Получение кластеров адресов",
+                "responses": {
+                    "200": {
+                        "description": "Кластеры получены",
+                        "schema": {
+                            "$ref": "#/definitions/v1.clustersResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный токен"
+                    }
+                }
+            }
+        },
+        "/admin/clusters/{entity}": {
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Задание адресов сущности, адреса переходят к ней от других сущностей\nКластеры, заданные через api, сохраняются при перечитывании файла",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Задание кластера адресов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор сущности",
+                        "name": "entity",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Адреса сущности",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.setClusterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Кластер задан"
+                    },
+                    "400": {
+                        "description": "Ошибка в запросе"
+                    },
+                    "401": {
+                        "description": "Неверный токен"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Удаление сущности, заданной через api",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Удаление кластера адресов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор сущности",
+                        "name": "entity",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Кластер удалён"
+                    },
+                    "401": {
+                        "description": "Неверный токен"
+                    },
+                    "404": {
+                        "description": "Кластер не найден"
+                    }
+                }
+            }
+        },
+        "/block_at": {
+            "get": {
+                "description": "Получение последнего блока, добытого не позже time\nБлок ищется бинарным поиском по заголовкам блоков",
+                "tags": [
+                    "StatsOfChanging"
+                ],
+                "summary": "Получение блока на момент времени",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Время в формате RFC 3339",
+                        "name": "time",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Блок найден",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BlockAtTime"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в запросе"
+                    },
+                    "500": {
+                        "description": "Не удалось выполнить запрос"
+                    },
+                    "504": {
+                        "description": "Таймаут запроса"
+                    }
+                }
+            }
+        },
         "/get_biggest_change": {
             "get": {
-                "description": "Получение адреса, который максимально изменился за count_of_blocks блоков\nПо умолчанию count_of_blocks = 100",
+                "description": "Получение адреса, который максимально изменился за count_of_blocks блоков\nПо умолчанию count_of_blocks = 100\nЕсли заданы from_block и to_block, то используются блоки [from_block; to_block]\nЕсли задана только одна граница, то окно из count_of_blocks блоков строится от неё\nЕсли заданы since и/или duration, то окно строится по времени блоков",
                 "tags": [
                     "StatsOfChanging"
                 ],
@@ -25,29 +337,458 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Количество последних блоков",
-                        "name": "count_of_blocks",
+                        "description": "Количество последних блоков",
+                        "name": "count_of_blocks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Первый блок",
+                        "name": "from_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Последний блок",
+                        "name": "to_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Адрес ERC-20 токена, изменения считаются в базовых единицах токена",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Блок, на котором заканчивается окно: latest, safe или finalized",
+                        "name": "anchor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало окна по времени блоков в формате RFC 3339",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Длительность окна по времени блоков, например 1h30m",
+                        "name": "duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Метрика ранжирования: net, inflow, outflow, gross, tx_count или relative, по умолчанию net",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Адреса, среди которых ищутся изменения",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Адреса, которые не учитываются при поиске",
+                        "name": "exclude",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Категории меток адресов, среди которых ищутся изменения",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Категории меток адресов, которые не учитываются",
+                        "name": "exclude_category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Группировка: address или entity, entity суммирует адреса одной сущности (только для net)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть балансы адресов до и после окна и изменение в процентах",
+                        "name": "balances",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть транзакции, которые сильнее всего изменили баланс адреса",
+                        "name": "explain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Адрес найден",
+                        "schema": {
+                            "$ref": "#/definitions/entity.BiggestChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в запросе"
+                    },
+                    "500": {
+                        "description": "Таймаут запроса"
+                    }
+                }
+            }
+        },
+        "/stream": {
+            "get": {
+                "description": "На каждый обработанный блок отправляется событие block с id, равным номеру блока\nДанные события: {\"block\": \"0x...\", \"change\": {...}, \"top\": {...}} для окна по умолчанию\nПри переподключении с заголовком Last-Event-ID присылаются пропущенные события из истории",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "StatsOfChanging"
+                ],
+                "summary": "Поток событий новых блоков (SSE)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Номер блока последнего полученного события",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "То же, что Last-Event-ID, для клиентов без заголовков",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Поток событий"
+                    },
+                    "400": {
+                        "description": "Неверный Last-Event-ID"
+                    }
+                }
+            }
+        },
+        "/top_changes": {
+            "get": {
+                "description": "Получение limit адресов, которые максимально изменились за count_of_blocks блоков\nАдреса с одинаковым изменением упорядочены по адресу\nПо умолчанию count_of_blocks = 100, limit = 10\nГраницы from_block и to_block задаются так же, как в /get_biggest_change",
+                "tags": [
+                    "StatsOfChanging"
+                ],
+                "summary": "Получение рейтинга адресов, которые максимально изменились",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Количество последних блоков",
+                        "name": "count_of_blocks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Первый блок",
+                        "name": "from_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Последний блок",
+                        "name": "to_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Адрес ERC-20 токена, изменения считаются в базовых единицах токена",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Блок, на котором заканчивается окно: latest, safe или finalized",
+                        "name": "anchor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало окна по времени блоков в формате RFC 3339",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Длительность окна по времени блоков, например 1h30m",
+                        "name": "duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Метрика ранжирования: net, inflow, outflow, gross, tx_count или relative, по умолчанию net",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Адреса, среди которых ищутся изменения",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Адреса, которые не учитываются при поиске",
+                        "name": "exclude",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Категории меток адресов, среди которых ищутся изменения",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Категории меток адресов, которые не учитываются",
+                        "name": "exclude_category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Группировка: address или entity, entity суммирует адреса одной сущности (только для net)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть балансы адресов до и после окна и изменение в процентах",
+                        "name": "balances",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество адресов в рейтинге, не больше maxTopLimit из конфига",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Адрес найден",
+                        "description": "Рейтинг получен",
                         "schema": {
-                            "$ref": "#/definitions/entity.BiggestChange"
+                            "$ref": "#/definitions/entity.TopChanges"
                         }
                     },
                     "400": {
                         "description": "Ошибка в запросе"
                     },
                     "500": {
+                        "description": "Не удалось выполнить запрос"
+                    },
+                    "504": {
                         "description": "Таймаут запроса"
                     }
                 }
             }
+        },
+        "/ws": {
+            "get": {
+                "description": "Соединение переключается на websocket, сообщения передаются в формате JSON\nПодписка: {\"type\": \"subscribe\", \"id\": \"a\", \"query\": {\"countOfBlocks\": 100, \"metric\": \"net\", \"limit\": 10}}\nЕсли limit не задан, присылается наибольшее изменение, иначе рейтинг из limit адресов\nТекущий результат присылается сразу, затем после каждого блока, который его изменил\nОтписка: {\"type\": \"unsubscribe\", \"id\": \"a\"}",
+                "tags": [
+                    "StatsOfChanging"
+                ],
+                "summary": "Подписка на изменения через websocket",
+                "responses": {
+                    "101": {
+                        "description": "Соединение переключено на websocket"
+                    },
+                    "503": {
+                        "description": "Превышено количество соединений"
+                    }
+                }
+            }
         }
     },
     "definitions": {
+        "entity.AddressChange": {
+            "description": "Изменение баланса адреса .",
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "amount": {
+                    "type": "string"
+                },
+                "endBalance": {
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "isNewContract": {
+                    "type": "boolean"
+                },
+                "isRecieved": {
+                    "type": "boolean"
+                },
+                "label": {
+                    "$ref": "#/definitions/entity.Label"
+                },
+                "percentChange": {
+                    "type": "number"
+                },
+                "startBalance": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AddressChanges": {
+            "description": "История изменений баланса адреса .",
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "anchor": {
+                    "type": "string"
+                },
+                "anchorBlock": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BlockChange"
+                    }
+                },
+                "countOfBlocks": {
+                    "type": "integer"
+                },
+                "firstBlock": {
+                    "type": "string"
+                },
+                "firstBlockTimestamp": {
+                    "type": "integer"
+                },
+                "isRecieved": {
+                    "type": "boolean"
+                },
+                "lastBlock": {
+                    "type": "string"
+                },
+                "lastBlockTimestamp": {
+                    "type": "integer"
+                },
+                "token": {
+                    "$ref": "#/definitions/entity.Token"
+                },
+                "total": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AddressFilters": {
+            "description": "Фильтры адресов, применённые при поиске .",
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "exclude": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "excludeCategories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "excludedByConfig": {
+                    "type": "integer"
+                },
+                "include": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.AlertRule": {
+            "description": "Правило оповещения .",
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "countOfBlocks": {
+                    "type": "integer"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "string"
+                },
+                "webhook": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.BiggestChange": {
             "description": "Наибольшее изменение .",
             "type": "object",
@@ -55,20 +796,288 @@ const docTemplate = `{
                 "address": {
                     "type": "string"
                 },
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "amount": {
                     "type": "string"
                 },
+                "anchor": {
+                    "type": "string"
+                },
+                "anchorBlock": {
+                    "type": "string"
+                },
+                "burnedFees": {
+                    "type": "string"
+                },
+                "computedAt": {
+                    "type": "string"
+                },
                 "countOfBlocks": {
                     "type": "integer"
                 },
+                "endBalance": {
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "failedTransactions": {
+                    "type": "integer"
+                },
+                "filters": {
+                    "$ref": "#/definitions/entity.AddressFilters"
+                },
+                "firstBlock": {
+                    "type": "string"
+                },
+                "firstBlockTimestamp": {
+                    "type": "integer"
+                },
+                "groupBy": {
+                    "type": "string"
+                },
+                "isNewContract": {
+                    "type": "boolean"
+                },
+                "isRecieved": {
+                    "type": "boolean"
+                },
+                "label": {
+                    "$ref": "#/definitions/entity.Label"
+                },
+                "lastBlock": {
+                    "type": "string"
+                },
+                "lastBlockTimestamp": {
+                    "type": "integer"
+                },
+                "metric": {
+                    "type": "string"
+                },
+                "percentChange": {
+                    "type": "number"
+                },
+                "stale": {
+                    "type": "boolean"
+                },
+                "startBalance": {
+                    "type": "string"
+                },
+                "token": {
+                    "$ref": "#/definitions/entity.Token"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ContributingTransaction"
+                    }
+                }
+            }
+        },
+        "entity.BlockAtTime": {
+            "description": "Последний блок на момент времени .",
+            "type": "object",
+            "properties": {
+                "hash": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.BlockChange": {
+            "description": "Изменение баланса адреса в блоке .",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "block": {
+                    "type": "string"
+                },
+                "isRecieved": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "entity.ContributingTransaction": {
+            "description": "Транзакция, которая изменила баланс адреса .",
+            "type": "object",
+            "properties": {
+                "block": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
                 "isRecieved": {
                     "type": "boolean"
                 },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Label": {
+            "description": "Метка адреса .",
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Token": {
+            "description": "ERC-20 токен .",
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "decimals": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.TopChanges": {
+            "description": "Рейтинг наибольших изменений .",
+            "type": "object",
+            "properties": {
+                "anchor": {
+                    "type": "string"
+                },
+                "anchorBlock": {
+                    "type": "string"
+                },
+                "burnedFees": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AddressChange"
+                    }
+                },
+                "countOfBlocks": {
+                    "type": "integer"
+                },
+                "failedTransactions": {
+                    "type": "integer"
+                },
+                "filters": {
+                    "$ref": "#/definitions/entity.AddressFilters"
+                },
+                "firstBlock": {
+                    "type": "string"
+                },
+                "firstBlockTimestamp": {
+                    "type": "integer"
+                },
+                "groupBy": {
+                    "type": "string"
+                },
                 "lastBlock": {
                     "type": "string"
+                },
+                "lastBlockTimestamp": {
+                    "type": "integer"
+                },
+                "metric": {
+                    "type": "string"
+                },
+                "token": {
+                    "$ref": "#/definitions/entity.Token"
+                }
+            }
+        },
+        "v1.alertRulesResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AlertRule"
+                    }
+                }
+            }
+        },
+        "v1.clustersResponse": {
+            "type": "object",
+            "properties": {
+                "clusters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "v1.setAlertRuleRequest": {
+            "type": "object",
+            "required": [
+                "threshold",
+                "webhook"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "countOfBlocks": {
+                    "type": "integer"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "string"
+                },
+                "webhook": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.setClusterRequest": {
+            "type": "object",
+            "required": [
+                "addresses"
+            ],
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
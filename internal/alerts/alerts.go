@@ -0,0 +1,258 @@
+// Package alerts implements alerts on big changes of addresses, which are delivered to webhooks.
+// Rules are checked on every block processed by follower.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/egor-denisov/biggest-change/internal/usecase"
+	sl "github.com/egor-denisov/biggest-change/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	_defaultMaxRetries = 5
+	_defaultBackoff    = time.Second
+	_maxBackoff        = time.Minute
+	_defaultTimeout    = 5 * time.Second
+	// Addresses of rule are got from top, so only this count of addresses can fire at one block.
+	// It's also limited by maximal limit of top in use case.
+	_maxAlertsPerRule = 100
+	_queueSize        = 100
+)
+
+var alertsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "biggest_change_alerts_total",
+	Help: "Count of alerts by result of delivery.",
+}, []string{"result"})
+
+// Addresses which are over threshold by rule.
+type ruleState struct {
+	condition entity.AlertRule // Rule without webhook, state is reset when it's changed
+	addresses map[string]struct{}
+}
+
+// Checking that address was over threshold, nil state hasn't any addresses.
+func (s *ruleState) has(addr string) bool {
+	if s == nil {
+		return false
+	}
+
+	_, ok := s.addresses[addr]
+
+	return ok
+}
+
+type Alerter struct {
+	sc         usecase.StatsOfChanging
+	rules      usecase.AlertRegistry
+	l          *slog.Logger
+	client     *http.Client
+	secret     []byte
+	maxRetries int
+	maxAlerts  uint // Maximal count of alerts of rule at one block
+	backoff    time.Duration
+	deadLetter io.WriteCloser // Alerter owns dead letter log and closes it on stop
+	deadMu     sync.Mutex
+	firing     map[string]*ruleState // States of rules by ids
+	queue      chan *delivery
+	notify     chan struct{}
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+func New(l *slog.Logger, sc usecase.StatsOfChanging, rules usecase.AlertRegistry, opts ...Option) *Alerter {
+	a := &Alerter{
+		sc:         sc,
+		rules:      rules,
+		l:          l,
+		client:     &http.Client{Timeout: _defaultTimeout},
+		maxRetries: _defaultMaxRetries,
+		maxAlerts:  _maxAlertsPerRule,
+		backoff:    _defaultBackoff,
+		firing:     make(map[string]*ruleState),
+		queue:      make(chan *delivery, _queueSize),
+		notify:     make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// Starting checking of rules and delivery of alerts.
+func (a *Alerter) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	a.done = make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		a.run(ctx)
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		a.send(ctx)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(a.done)
+	}()
+}
+
+// Stopping checking of rules, alerts which aren't delivered are written to dead letters.
+// Queue is drained after both goroutines are stopped, so alerts enqueued during stop aren't lost.
+func (a *Alerter) Stop() {
+	if a.cancel != nil {
+		a.cancel()
+		<-a.done
+	}
+
+	// Queue isn't used by anyone else after stop
+	for len(a.queue) > 0 {
+		a.dead(<-a.queue, 0, context.Canceled)
+	}
+
+	a.closeDeadLetter()
+}
+
+// Notifying alerter about new block, it's listener of follower.
+// Blocks which come during checking are joined, so slow checking doesn't block follower.
+func (a *Alerter) Notify(_ *big.Int) {
+	select {
+	case a.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (a *Alerter) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.notify:
+			for _, alert := range a.evaluate(ctx) {
+				a.enqueue(alert)
+			}
+		}
+	}
+}
+
+// Checking all rules and getting alerts of addresses which reached threshold since last check.
+// Address over threshold fires again only after it falls below threshold.
+// State is kept by id of rule and it's reset only if condition of rule is changed,
+// so rule with new threshold fires again for addresses which are over it, but new webhook doesn't.
+func (a *Alerter) evaluate(ctx context.Context) []*delivery {
+	var res []*delivery
+
+	rules := a.rules.GetRules()
+	firing := make(map[string]*ruleState, len(rules))
+
+	for _, rule := range rules {
+		condition := rule
+		condition.Webhook = ""
+
+		prev := a.firing[rule.ID]
+		if prev != nil && prev.condition != condition {
+			prev = nil
+		}
+
+		alerts, err := a.check(ctx, rule)
+		if err != nil {
+			if ctx.Err() == nil {
+				a.l.Error("alerts - Alerter - evaluate", "rule", rule.ID, sl.Err(err))
+			}
+			// Rule isn't reset by error, so alerts aren't repeated after it
+			if prev != nil {
+				firing[rule.ID] = prev
+			}
+
+			continue
+		}
+
+		state := &ruleState{condition: condition, addresses: make(map[string]struct{}, len(alerts))}
+		firing[rule.ID] = state
+
+		for _, alert := range alerts {
+			state.addresses[alert.Address] = struct{}{}
+
+			if !prev.has(alert.Address) {
+				res = append(res, &delivery{alert: alert, webhook: rule.Webhook})
+			}
+		}
+	}
+
+	a.firing = firing
+
+	return res
+}
+
+// Getting changes of addresses of rule which are over threshold.
+func (a *Alerter) check(ctx context.Context, rule entity.AlertRule) ([]*entity.Alert, error) {
+	query := entity.ChangesQuery{CountOfBlocks: rule.CountOfBlocks}
+	if rule.Address != "" {
+		query.Include = []string{rule.Address}
+	}
+
+	if rule.Category != "" {
+		query.Categories = []string{rule.Category}
+	}
+
+	top, err := a.sc.GetTopChanges(ctx, query, a.maxAlerts)
+	if err != nil {
+		return nil, fmt.Errorf("Alerter - check - a.sc.GetTopChanges: %w", err)
+	}
+
+	threshold, _ := parseThreshold(rule.Threshold)
+
+	var res []*entity.Alert
+
+	for _, change := range top.Changes {
+		amount, ok := new(big.Int).SetString(change.Amount, 0)
+		if !ok {
+			return nil, fmt.Errorf("Alerter - check: %w: %s", entity.ErrStringIsNotHex, change.Amount)
+		}
+		// Changes are ordered by amount, so the rest are below threshold
+		if amount.Cmp(threshold) < 0 {
+			break
+		}
+
+		if (rule.Direction == entity.DirectionIn && !change.IsRecieved) ||
+			(rule.Direction == entity.DirectionOut && change.IsRecieved) {
+			continue
+		}
+
+		res = append(res, &entity.Alert{
+			Rule:          rule.ID,
+			Address:       change.Address,
+			Label:         change.Label,
+			Amount:        change.Amount,
+			Threshold:     fmt.Sprintf("%#x", threshold),
+			IsRecieved:    change.IsRecieved,
+			FirstBlock:    top.FirstBlock,
+			LastBlock:     top.LastBlock,
+			CountOfBlocks: top.CountOfBlocks,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,251 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	mock "github.com/egor-denisov/biggest-change/internal/usecase/mocks"
+	"github.com/egor-denisov/biggest-change/pkg/logger"
+	"github.com/go-playground/assert"
+	"github.com/golang/mock/gomock"
+)
+
+// Dead letter log in memory, which remembers that it's closed.
+type deadLetterBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *deadLetterBuffer) Close() error {
+	b.closed = true
+
+	return nil
+}
+
+// Getting addresses of alerts in order of delivery.
+func addresses(deliveries []*delivery) []string {
+	res := make([]string, 0, len(deliveries))
+	for _, d := range deliveries {
+		res = append(res, d.alert.Address)
+	}
+
+	return res
+}
+
+func Test_Alerter_evaluate(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	sc := mock.NewMockStatsOfChanging(c)
+
+	rules, err := NewRules([]entity.AlertRule{
+		{ID: "in", Threshold: "100", Direction: entity.DirectionIn, Webhook: "https://example.com/hook"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := New(logger.SetupLogger("debug"), sc, rules)
+
+	top := func(changes ...*entity.AddressChange) {
+		sc.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{}, uint(_maxAlertsPerRule)).
+			Return(&entity.TopChanges{Changes: changes, FirstBlock: "0x1", LastBlock: "0x64", CountOfBlocks: 100}, nil)
+	}
+
+	// Outgoing change is filtered by direction, change below threshold stops checking
+	top(
+		&entity.AddressChange{Address: "0x1", Amount: "0x100", IsRecieved: true},
+		&entity.AddressChange{Address: "0x2", Amount: "0x90", IsRecieved: false},
+		&entity.AddressChange{Address: "0x3", Amount: "0x10", IsRecieved: true},
+	)
+
+	res := a.evaluate(context.Background())
+	assert.Equal(t, addresses(res), []string{"0x1"})
+	assert.Equal(t, res[0].alert.Threshold, "0x64")
+	assert.Equal(t, res[0].alert.LastBlock, "0x64")
+	assert.Equal(t, res[0].webhook, "https://example.com/hook")
+
+	// Address which is still over threshold doesn't fire again
+	top(
+		&entity.AddressChange{Address: "0x4", Amount: "0x200", IsRecieved: true},
+		&entity.AddressChange{Address: "0x1", Amount: "0x100", IsRecieved: true},
+	)
+	assert.Equal(t, addresses(a.evaluate(context.Background())), []string{"0x4"})
+
+	// Error of use case doesn't reset state of rule
+	sc.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{}, uint(_maxAlertsPerRule)).
+		Return(nil, entity.ErrInternalServer)
+	assert.Equal(t, len(a.evaluate(context.Background())), 0)
+
+	// Address fires again after it fell below threshold
+	top(&entity.AddressChange{Address: "0x4", Amount: "0x200", IsRecieved: true})
+	assert.Equal(t, len(a.evaluate(context.Background())), 0)
+
+	top(
+		&entity.AddressChange{Address: "0x1", Amount: "0x100", IsRecieved: true},
+		&entity.AddressChange{Address: "0x4", Amount: "0x200", IsRecieved: true},
+	)
+	assert.Equal(t, addresses(a.evaluate(context.Background())), []string{"0x1"})
+
+	// Rule with new webhook keeps its state
+	err = rules.SetRule(entity.AlertRule{
+		ID: "in", Threshold: "100", Direction: entity.DirectionIn, Webhook: "https://example.com/new",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	top(
+		&entity.AddressChange{Address: "0x1", Amount: "0x100", IsRecieved: true},
+		&entity.AddressChange{Address: "0x4", Amount: "0x200", IsRecieved: true},
+	)
+	assert.Equal(t, len(a.evaluate(context.Background())), 0)
+
+	// Rule with new condition fires again for addresses which are over threshold
+	err = rules.SetRule(entity.AlertRule{ID: "in", Threshold: "200", Webhook: "https://example.com/hook"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	top(
+		&entity.AddressChange{Address: "0x1", Amount: "0x100", IsRecieved: true},
+		&entity.AddressChange{Address: "0x4", Amount: "0x200", IsRecieved: true},
+	)
+	assert.Equal(t, addresses(a.evaluate(context.Background())), []string{"0x1", "0x4"})
+}
+
+func Test_Alerter_MaxTopLimit(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	sc := mock.NewMockStatsOfChanging(c)
+
+	rules, err := NewRules([]entity.AlertRule{{ID: "whales", Threshold: "100", Webhook: "https://example.com/hook"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Limit greater than maximal limit of use case would be rejected by it
+	a := New(logger.SetupLogger("debug"), sc, rules, MaxTopLimit(10))
+
+	sc.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{}, uint(10)).
+		Return(&entity.TopChanges{Changes: []*entity.AddressChange{{Address: "0x1", Amount: "0x100"}}}, nil)
+
+	assert.Equal(t, addresses(a.evaluate(context.Background())), []string{"0x1"})
+}
+
+func Test_Alerter_deliver(t *testing.T) {
+	for _, test := range testsDeliver {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts atomic.Int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+				assert.Equal(t, err, nil)
+				assert.Equal(t, time.Since(time.Unix(timestamp, 0)) < time.Minute, true)
+				assert.Equal(t, r.Header.Get(SignatureHeader), Sign([]byte("secret"), r.Header.Get(TimestampHeader), body))
+				assert.Equal(t, strings.Contains(string(body), `"rule":"whales"`), true)
+
+				w.WriteHeader(test.statuses[attempts.Add(1)-1])
+			}))
+			defer server.Close()
+
+			var deadLetters deadLetterBuffer
+
+			a := New(logger.SetupLogger("debug"), nil, nil,
+				Secret("secret"), MaxRetries(2), Backoff(time.Millisecond), DeadLetter(&deadLetters))
+
+			a.deliver(context.Background(), &delivery{
+				alert:   &entity.Alert{Rule: "whales", Address: "0x1", Amount: "0x100"},
+				webhook: server.URL,
+			})
+
+			assert.Equal(t, int(attempts.Load()), test.expectedAttempts)
+
+			if !test.isDead {
+				assert.Equal(t, deadLetters.Len(), 0)
+
+				return
+			}
+
+			var line deadLetter
+			if err := json.Unmarshal(deadLetters.Bytes(), &line); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, line.Alert.Rule, "whales")
+			assert.Equal(t, line.Webhook, server.URL)
+			assert.Equal(t, line.Attempts, test.expectedAttempts)
+		})
+	}
+}
+
+func Test_Alerter_delay(t *testing.T) {
+	a := New(logger.SetupLogger("debug"), nil, nil, Backoff(time.Second))
+
+	assert.Equal(t, a.delay(1), time.Second)
+	assert.Equal(t, a.delay(3), 4*time.Second)
+	// Delay is limited, so large count of attempts doesn't overflow it
+	assert.Equal(t, a.delay(7), _maxBackoff)
+	assert.Equal(t, a.delay(100), _maxBackoff)
+}
+
+func Test_Alerter_Stop(t *testing.T) {
+	var deadLetters deadLetterBuffer
+
+	a := New(logger.SetupLogger("debug"), nil, nil, DeadLetter(&deadLetters))
+
+	a.enqueue(&delivery{alert: &entity.Alert{Rule: "whales", Address: "0x1"}, webhook: "https://example.com/hook"})
+	a.enqueue(&delivery{alert: &entity.Alert{Rule: "whales", Address: "0x2"}, webhook: "https://example.com/hook"})
+
+	// Alerts left in queue are written to dead letters before log is closed
+	a.Stop()
+	assert.Equal(t, strings.Count(deadLetters.String(), "\n"), 2)
+	assert.Equal(t, deadLetters.closed, true)
+
+	// Alert which fails after stop is only logged
+	a.dead(&delivery{alert: &entity.Alert{Rule: "whales", Address: "0x3"}}, 1, ErrWebhookResponse)
+	assert.Equal(t, strings.Count(deadLetters.String(), "\n"), 2)
+}
+
+var testsDeliver = []struct {
+	name             string
+	statuses         []int
+	expectedAttempts int
+	isDead           bool
+}{
+	{
+		name:             "delivered",
+		statuses:         []int{http.StatusOK},
+		expectedAttempts: 1,
+	},
+	{
+		name:             "delivered after retries",
+		statuses:         []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent},
+		expectedAttempts: 3,
+	},
+	{
+		name:             "retries are exhausted",
+		statuses:         []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+		expectedAttempts: 3,
+		isDead:           true,
+	},
+	{
+		name:             "rejected",
+		statuses:         []int{http.StatusBadRequest},
+		expectedAttempts: 1,
+		isDead:           true,
+	},
+}
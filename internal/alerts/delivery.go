@@ -0,0 +1,209 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	sl "github.com/egor-denisov/biggest-change/pkg/logger"
+)
+
+const (
+	// Header with HMAC-SHA256 of timestamp and body, it's set only if secret is configured.
+	SignatureHeader = "X-Signature-256"
+	// Header with unix time of request, receiver rejects old requests to prevent replay of alerts.
+	TimestampHeader = "X-Timestamp"
+)
+
+var (
+	ErrQueueFull       = errors.New("queue of alerts is full")
+	ErrWebhookResponse = errors.New("webhook returned error")
+	ErrWebhookRejected = errors.New("webhook rejected alert")
+)
+
+type delivery struct {
+	alert   *entity.Alert
+	webhook string
+}
+
+// Record of dead letter log, it's written as JSON line.
+type deadLetter struct {
+	Alert    *entity.Alert `json:"alert"`
+	Webhook  string        `json:"webhook"`
+	Attempts int           `json:"attempts"`
+	Error    string        `json:"error"`
+	FailedAt time.Time     `json:"failedAt"`
+}
+
+// Getting signature of timestamp and body in format of SignatureHeader.
+// Signed message is timestamp, dot and body, so timestamp can't be changed without secret.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Adding alert to queue of delivery, alert is written to dead letters if queue is full.
+func (a *Alerter) enqueue(d *delivery) {
+	d.alert.CreatedAt = time.Now().UTC()
+
+	select {
+	case a.queue <- d:
+	default:
+		a.dead(d, 0, ErrQueueFull)
+	}
+}
+
+// Delivering alerts one by one, alerts which are left in queue on stop are written to dead letters by Stop.
+func (a *Alerter) send(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-a.queue:
+			a.deliver(ctx, d)
+		}
+	}
+}
+
+// Posting alert to webhook with retries, backoff is doubled after each attempt up to maximal backoff.
+// Alert rejected by webhook isn't retried.
+func (a *Alerter) deliver(ctx context.Context, d *delivery) {
+	body, err := json.Marshal(d.alert)
+	if err != nil {
+		a.dead(d, 0, fmt.Errorf("Alerter - deliver - json.Marshal: %w", err))
+
+		return
+	}
+
+	attempt := 1
+
+	for ; ; attempt++ {
+		if err = a.post(ctx, d.webhook, body); err == nil {
+			alertsTotal.WithLabelValues("delivered").Inc()
+
+			return
+		}
+
+		if errors.Is(err, ErrWebhookRejected) || attempt > a.maxRetries {
+			break
+		}
+
+		timer := time.NewTimer(a.delay(attempt))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			a.dead(d, attempt, ctx.Err())
+
+			return
+		case <-timer.C:
+		}
+	}
+
+	a.dead(d, attempt, err)
+}
+
+// Getting pause after attempt, it's doubled until it reaches maximal backoff, so it can't overflow.
+func (a *Alerter) delay(attempt int) time.Duration {
+	res := a.backoff
+
+	for i := 1; i < attempt && res < _maxBackoff; i++ {
+		res *= 2
+	}
+
+	return min(res, _maxBackoff)
+}
+
+func (a *Alerter) post(ctx context.Context, webhook string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("Alerter - post - http.NewRequestWithContext: %w", err)
+	}
+
+	// Every attempt has its own timestamp, so retried alert isn't rejected as old one
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+
+	if len(a.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(a.secret, timestamp, body))
+	}
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Alerter - post - a.client.Do: %w", err)
+	}
+	defer res.Body.Close()
+	// Body is read, so connection can be reused
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	switch {
+	case res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices:
+		return nil
+	case res.StatusCode >= http.StatusBadRequest && res.StatusCode < http.StatusInternalServerError &&
+		res.StatusCode != http.StatusRequestTimeout && res.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("Alerter - post: %w: status %d", ErrWebhookRejected, res.StatusCode)
+	default:
+		return fmt.Errorf("Alerter - post: %w: status %d", ErrWebhookResponse, res.StatusCode)
+	}
+}
+
+// Writing alert which isn't delivered to dead letter log.
+func (a *Alerter) dead(d *delivery, attempts int, err error) {
+	alertsTotal.WithLabelValues("dead").Inc()
+	a.l.Error("alerts - Alerter - dead letter", "rule", d.alert.Rule, "webhook", d.webhook, sl.Err(err))
+
+	line, mErr := json.Marshal(&deadLetter{
+		Alert:    d.alert,
+		Webhook:  d.webhook,
+		Attempts: attempts,
+		Error:    err.Error(),
+		FailedAt: time.Now().UTC(),
+	})
+	if mErr != nil {
+		a.l.Error("alerts - Alerter - dead letter - json.Marshal", sl.Err(mErr))
+
+		return
+	}
+
+	a.deadMu.Lock()
+	defer a.deadMu.Unlock()
+	// Log is closed on stop
+	if a.deadLetter == nil {
+		return
+	}
+
+	if _, wErr := a.deadLetter.Write(append(line, '\n')); wErr != nil {
+		a.l.Error("alerts - Alerter - dead letter - write", sl.Err(wErr))
+	}
+}
+
+// Closing dead letter log, alerts which fail after it are only logged.
+func (a *Alerter) closeDeadLetter() {
+	a.deadMu.Lock()
+	defer a.deadMu.Unlock()
+
+	if a.deadLetter == nil {
+		return
+	}
+
+	if err := a.deadLetter.Close(); err != nil {
+		a.l.Error("alerts - Alerter - dead letter - close", sl.Err(err))
+	}
+
+	a.deadLetter = nil
+}
@@ -0,0 +1,44 @@
+package alerts
+
+import (
+	"io"
+	"time"
+)
+
+type Option func(*Alerter)
+
+func Secret(secret string) Option {
+	return func(a *Alerter) {
+		a.secret = []byte(secret)
+	}
+}
+
+func MaxRetries(maxRetries int) Option {
+	return func(a *Alerter) {
+		a.maxRetries = maxRetries
+	}
+}
+
+func MaxTopLimit(maxTopLimit uint) Option {
+	return func(a *Alerter) {
+		a.maxAlerts = min(_maxAlertsPerRule, maxTopLimit)
+	}
+}
+
+func Backoff(backoff time.Duration) Option {
+	return func(a *Alerter) {
+		a.backoff = backoff
+	}
+}
+
+func Timeout(timeout time.Duration) Option {
+	return func(a *Alerter) {
+		a.client.Timeout = timeout
+	}
+}
+
+func DeadLetter(w io.WriteCloser) Option {
+	return func(a *Alerter) {
+		a.deadLetter = w
+	}
+}
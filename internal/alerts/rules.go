@@ -0,0 +1,143 @@
+package alerts
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Rules from config can't be deleted, rules set through api are kept in memory and override them.
+type Rules struct {
+	mu         sync.RWMutex
+	fromConfig map[string]entity.AlertRule
+	fromAPI    map[string]entity.AlertRule
+}
+
+// New rules are checked in the same way as rules set through api.
+func NewRules(rules []entity.AlertRule) (*Rules, error) {
+	r := &Rules{
+		fromConfig: make(map[string]entity.AlertRule, len(rules)),
+		fromAPI:    make(map[string]entity.AlertRule),
+	}
+
+	for _, rule := range rules {
+		rule, err := normalizeRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("Rules - NewRules - normalizeRule: %w", err)
+		}
+
+		r.fromConfig[rule.ID] = rule
+	}
+
+	return r, nil
+}
+
+// Getting all rules ordered by id.
+func (r *Rules) GetRules() []entity.AlertRule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]entity.AlertRule, 0, len(r.fromConfig)+len(r.fromAPI))
+
+	for id, rule := range r.fromConfig {
+		if _, ok := r.fromAPI[id]; !ok {
+			res = append(res, rule)
+		}
+	}
+
+	for _, rule := range r.fromAPI {
+		res = append(res, rule)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+
+	return res
+}
+
+// Setting rule, rule with the same id is replaced.
+func (r *Rules) SetRule(rule entity.AlertRule) error {
+	rule, err := normalizeRule(rule)
+	if err != nil {
+		return fmt.Errorf("Rules - SetRule - normalizeRule: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fromAPI[rule.ID] = rule
+
+	return nil
+}
+
+// Deleting rule set through api, rule from config is restored if it was overridden.
+func (r *Rules) DeleteRule(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.fromAPI[id]; !ok {
+		return fmt.Errorf("Rules - DeleteRule: %w", entity.ErrAlertRuleNotFound)
+	}
+
+	delete(r.fromAPI, id)
+
+	return nil
+}
+
+// Checking rule and bringing its fields to canonical form.
+// Direction is any by default, threshold is kept as decimal number.
+func normalizeRule(rule entity.AlertRule) (entity.AlertRule, error) {
+	rule.ID = strings.TrimSpace(rule.ID)
+	if rule.ID == "" {
+		return rule, fmt.Errorf("%w: empty id", entity.ErrInvalidAlertRule)
+	}
+
+	threshold, ok := parseThreshold(rule.Threshold)
+	if !ok {
+		return rule, fmt.Errorf("%w: invalid threshold %q", entity.ErrInvalidAlertRule, rule.Threshold)
+	}
+
+	rule.Threshold = threshold.String()
+
+	switch rule.Direction = strings.ToLower(strings.TrimSpace(rule.Direction)); rule.Direction {
+	case "":
+		rule.Direction = entity.DirectionAny
+	case entity.DirectionIn, entity.DirectionOut, entity.DirectionAny:
+	default:
+		return rule, fmt.Errorf("%w: invalid direction %q", entity.ErrInvalidAlertRule, rule.Direction)
+	}
+
+	if rule.Address != "" {
+		addr, err := entity.NormalizeAddress(rule.Address)
+		if err != nil {
+			return rule, fmt.Errorf("%w: invalid address %q", entity.ErrInvalidAlertRule, rule.Address)
+		}
+
+		rule.Address = addr
+	}
+
+	rule.Category = strings.TrimSpace(rule.Category)
+
+	u, err := url.Parse(rule.Webhook)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return rule, fmt.Errorf("%w: invalid webhook %q", entity.ErrInvalidAlertRule, rule.Webhook)
+	}
+
+	return rule, nil
+}
+
+// Parsing positive threshold in decimal or hex format.
+func parseThreshold(s string) (*big.Int, bool) {
+	s = strings.TrimSpace(s)
+
+	res, ok := new(big.Int).SetString(s, 0)
+	if !ok || res.Sign() <= 0 {
+		return nil, false
+	}
+
+	return res, true
+}
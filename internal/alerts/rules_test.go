@@ -0,0 +1,105 @@
+package alerts
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/go-playground/assert"
+)
+
+func Test_normalizeRule(t *testing.T) {
+	for _, test := range testsNormalizeRule {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := normalizeRule(test.rule)
+
+			assert.Equal(t, errors.Is(err, entity.ErrInvalidAlertRule), test.isInvalid)
+
+			if !test.isInvalid {
+				assert.Equal(t, res, test.expected)
+			}
+		})
+	}
+}
+
+var testsNormalizeRule = []struct {
+	name      string
+	rule      entity.AlertRule
+	expected  entity.AlertRule
+	isInvalid bool
+}{
+	{
+		name: "defaults",
+		rule: entity.AlertRule{ID: " whales ", Threshold: "0x3e8", Webhook: "https://example.com/hook"},
+		expected: entity.AlertRule{
+			ID:        "whales",
+			Threshold: "1000",
+			Direction: entity.DirectionAny,
+			Webhook:   "https://example.com/hook",
+		},
+	},
+	{
+		name: "address is lowercased",
+		rule: entity.AlertRule{
+			ID:        "whales",
+			Threshold: "1000",
+			Direction: "OUT",
+			Address:   "0xDAC17F958D2EE523A2206206994597C13D831EC7",
+			Webhook:   "http://localhost:9000",
+		},
+		expected: entity.AlertRule{
+			ID:        "whales",
+			Threshold: "1000",
+			Direction: entity.DirectionOut,
+			Address:   "0xdac17f958d2ee523a2206206994597c13d831ec7",
+			Webhook:   "http://localhost:9000",
+		},
+	},
+	{
+		name:      "empty id",
+		rule:      entity.AlertRule{Threshold: "1000", Webhook: "https://example.com/hook"},
+		isInvalid: true,
+	},
+	{
+		name:      "negative threshold",
+		rule:      entity.AlertRule{ID: "whales", Threshold: "-1", Webhook: "https://example.com/hook"},
+		isInvalid: true,
+	},
+	{
+		name:      "invalid direction",
+		rule:      entity.AlertRule{ID: "whales", Threshold: "1", Direction: "up", Webhook: "https://example.com/hook"},
+		isInvalid: true,
+	},
+	{
+		name:      "invalid address",
+		rule:      entity.AlertRule{ID: "whales", Threshold: "1", Address: "0x1", Webhook: "https://example.com/hook"},
+		isInvalid: true,
+	},
+	{
+		name:      "invalid webhook",
+		rule:      entity.AlertRule{ID: "whales", Threshold: "1", Webhook: "ftp://example.com"},
+		isInvalid: true,
+	},
+}
+
+func Test_Rules(t *testing.T) {
+	r, err := NewRules([]entity.AlertRule{
+		{ID: "whales", Threshold: "1000", Webhook: "https://example.com/config"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rule from api overrides rule from config
+	err = r.SetRule(entity.AlertRule{ID: "whales", Threshold: "10", Webhook: "https://example.com/api"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, r.GetRules()[0].Webhook, "https://example.com/api")
+
+	// Rule from config is restored after deleting
+	assert.Equal(t, r.DeleteRule("whales"), nil)
+	assert.Equal(t, r.GetRules()[0].Webhook, "https://example.com/config")
+
+	// Rule from config can't be deleted
+	assert.Equal(t, errors.Is(r.DeleteRule("whales"), entity.ErrAlertRuleNotFound), true)
+	assert.Equal(t, len(r.GetRules()), 1)
+}
@@ -2,9 +2,16 @@ package app
 
 import (
 	"log/slog"
+	"os"
 
 	"github.com/egor-denisov/biggest-change/config"
+	"github.com/egor-denisov/biggest-change/internal/alerts"
+	"github.com/egor-denisov/biggest-change/internal/clusters"
 	v1 "github.com/egor-denisov/biggest-change/internal/controller/http/v1"
+	"github.com/egor-denisov/biggest-change/internal/controller/sse"
+	"github.com/egor-denisov/biggest-change/internal/controller/ws"
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/egor-denisov/biggest-change/internal/labels"
 	"github.com/egor-denisov/biggest-change/internal/usecase"
 	webapi "github.com/egor-denisov/biggest-change/internal/webapi/getblock"
 	"github.com/egor-denisov/biggest-change/pkg/httpserver"
@@ -14,6 +21,12 @@ import (
 
 type App struct {
 	HTTPServer *httpserver.Server
+	Labels     *labels.Store
+	Clusters   *clusters.Store
+	Follower   *usecase.Follower
+	Hub        *ws.Hub
+	Stream     *sse.Stream
+	Alerter    *alerts.Alerter
 }
 
 func New(
@@ -28,23 +41,131 @@ func New(
 		webapi.Timeout(cfg.API.Timeout),
 		webapi.MaxRetries(cfg.API.MaxRetries),
 		webapi.TimeBetweenRetries(cfg.API.TimeBetweenRetries),
+		webapi.Tracing(cfg.API.Tracing),
+		webapi.LogsBlockRange(cfg.API.LogsBlockRange),
 	)
 
+	// Labels of addresses
+	labelStore := labels.New(cfg.App.LabelsFile)
+	if err := labelStore.Reload(); err != nil {
+		panic("cannot load labels: " + err.Error())
+	}
+
+	// Entities which own groups of addresses
+	clusterStore := clusters.New(cfg.App.ClustersFile)
+	if err := clusterStore.Reload(); err != nil {
+		panic("cannot load clusters: " + err.Error())
+	}
+
 	// Use case
 	statsOfChangingUseCase := usecase.New(
 		api,
 		usecase.CacheSize(cfg.App.CacheSize),
+		usecase.HeaderCacheSize(cfg.App.HeaderCacheSize),
 		usecase.MaxGoroutines(cfg.App.MaxGoroutines),
 		usecase.AverageAddressCountInBlock(cfg.App.AverageAddressesInBlock),
 		usecase.CountOfBlocks(cfg.App.CountOfBlocks),
+		usecase.MaxCountOfBlocks(cfg.App.MaxCountOfBlocks),
+		usecase.TopLimit(cfg.App.TopLimit),
+		usecase.MaxTopLimit(cfg.App.MaxTopLimit),
+		usecase.Withdrawals(cfg.App.Withdrawals),
+		usecase.ConfirmationDepth(cfg.App.ConfirmationDepth),
+		usecase.Anchor(cfg.App.Anchor),
+		usecase.Exclude(cfg.App.Exclude),
+		usecase.Labels(labelStore),
+		usecase.Clusters(clusterStore),
+		usecase.RelativeCandidates(cfg.App.RelativeCandidates),
+		usecase.RelativeMinBalance(cfg.App.RelativeMinBalance),
+		usecase.StaleAfter(cfg.App.StaleAfter),
 	)
 
+	// Following default window in background
+	follower := usecase.NewFollower(statsOfChangingUseCase, log, cfg.App.FollowInterval)
+	// Precomputed result contains labels and entities, so it's dropped when they are changed
+	labelStore.OnChange(statsOfChangingUseCase.InvalidateHotResult)
+	clusterStore.OnChange(statsOfChangingUseCase.InvalidateHotResult)
+
+	// Websocket subscriptions are updated on new blocks of follower
+	hub := ws.New(
+		log,
+		statsOfChangingUseCase,
+		ws.MaxClients(cfg.HTTP.WSMaxClients),
+		ws.MaxSubscriptions(cfg.HTTP.WSMaxSubscriptions),
+		ws.PongWait(cfg.HTTP.WSPongWait),
+		ws.MaxQueries(cfg.HTTP.WSMaxQueries),
+		ws.MaxCountOfBlocks(cfg.HTTP.WSMaxBlocks),
+		ws.Workers(cfg.HTTP.WSWorkers),
+		ws.ComputeTimeout(cfg.HTTP.WSComputeTimeout),
+	)
+	follower.OnBlock(hub.Notify)
+	hub.Start()
+
+	// Events of new blocks for server-sent events
+	stream := sse.New(log, statsOfChangingUseCase, sse.History(cfg.HTTP.SSEHistory))
+	follower.OnBlock(stream.Notify)
+	stream.Start()
+
+	// Rules of alerts are checked on new blocks of follower
+	alertRules, err := alerts.NewRules(convertAlertRules(cfg.Alerts.Rules))
+	if err != nil {
+		panic("cannot load alert rules: " + err.Error())
+	}
+
+	alertOpts := []alerts.Option{
+		alerts.Secret(cfg.Alerts.Secret),
+		alerts.MaxRetries(cfg.Alerts.MaxRetries),
+		alerts.MaxTopLimit(cfg.App.MaxTopLimit),
+		alerts.Backoff(cfg.Alerts.Backoff),
+		alerts.Timeout(cfg.Alerts.Timeout),
+	}
+
+	if cfg.Alerts.DeadLetterFile != "" {
+		deadLetter, err := os.OpenFile(cfg.Alerts.DeadLetterFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			panic("cannot open dead letter file: " + err.Error())
+		}
+
+		// File is closed by alerter on stop
+		alertOpts = append(alertOpts, alerts.DeadLetter(deadLetter))
+	}
+
+	alerter := alerts.New(log, statsOfChangingUseCase, alertRules, alertOpts...)
+	follower.OnBlock(alerter.Notify)
+	alerter.Start()
+
+	follower.Start()
+
 	// Init http server
 	handler := gin.New()
-	v1.NewRouter(handler, log, statsOfChangingUseCase)
+	v1.NewRouter(handler, log, statsOfChangingUseCase, clusterStore, alertRules, hub, stream, cfg.HTTP.AdminToken)
 	httpServer := httpserver.New(log, handler, httpserver.Port(cfg.HTTP.Port), httpserver.WriteTimeout(cfg.HTTP.Timeout))
 
 	return &App{
 		HTTPServer: httpServer,
+		Labels:     labelStore,
+		Clusters:   clusterStore,
+		Follower:   follower,
+		Hub:        hub,
+		Stream:     stream,
+		Alerter:    alerter,
 	}
 }
+
+// Converting rules of alerts from config, they are checked by store of rules.
+func convertAlertRules(rules []config.AlertRule) []entity.AlertRule {
+	res := make([]entity.AlertRule, 0, len(rules))
+
+	for _, rule := range rules {
+		res = append(res, entity.AlertRule{
+			ID:            rule.ID,
+			Threshold:     rule.Threshold,
+			Direction:     rule.Direction,
+			CountOfBlocks: rule.CountOfBlocks,
+			Address:       rule.Address,
+			Category:      rule.Category,
+			Webhook:       rule.Webhook,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,188 @@
+// Package clusters implements registry of entities, which own groups of addresses.
+// Clusters are loaded from local YAML or CSV file and can be changed through admin api.
+package clusters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/egor-denisov/biggest-change/internal/records"
+)
+
+var ErrUnknownFormat = records.ErrUnknownFormat
+
+// Clusters from file are replaced on reload, clusters set through api are kept and override them.
+type Store struct {
+	path      string
+	mu        sync.RWMutex
+	fromFile  map[string][]string
+	fromAPI   map[string][]string
+	entities  map[string]string
+	listeners []func()
+}
+
+type clusterRecord struct {
+	Address string `yaml:"address"`
+	Entity  string `yaml:"entity"`
+}
+
+// New store reads clusters from path, store without path has only clusters set through api.
+func New(path string) *Store {
+	return &Store{
+		path:     path,
+		fromFile: make(map[string][]string),
+		fromAPI:  make(map[string][]string),
+		entities: make(map[string]string),
+	}
+}
+
+// Reading clusters from file again, clusters are kept if file can't be read.
+func (s *Store) Reload() error {
+	if s.path == "" {
+		return nil
+	}
+
+	rs, err := records.Read(s.path, clusterFromRow)
+	if err != nil {
+		return fmt.Errorf("ClusterStore - Reload - records.Read: %w", err)
+	}
+
+	fromFile := make(map[string][]string)
+
+	for i, r := range rs {
+		addr, err := entity.NormalizeAddress(r.Address)
+		if err != nil || strings.TrimSpace(r.Entity) == "" {
+			return fmt.Errorf("ClusterStore - Reload - record %d: %w", i+1, entity.ErrInvalidCluster)
+		}
+
+		id := strings.TrimSpace(r.Entity)
+		fromFile[id] = append(fromFile[id], addr)
+	}
+
+	s.mu.Lock()
+	s.fromFile = fromFile
+	s.reindex()
+	s.mu.Unlock()
+
+	s.notify()
+
+	return nil
+}
+
+// Getting entity which owns address, empty string is returned for address without entity.
+func (s *Store) Entity(address string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.entities[strings.ToLower(address)]
+}
+
+// Getting addresses of all entities.
+func (s *Store) GetClusters() map[string][]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make(map[string][]string)
+
+	for addr, id := range s.entities {
+		res[id] = append(res[id], addr)
+	}
+
+	for _, addrs := range res {
+		sort.Strings(addrs)
+	}
+
+	return res
+}
+
+// Setting addresses of entity, they are moved from entities which owned them before.
+func (s *Store) SetCluster(entityID string, addresses []string) error {
+	entityID = strings.TrimSpace(entityID)
+	if entityID == "" || len(addresses) == 0 {
+		return fmt.Errorf("ClusterStore - SetCluster: %w", entity.ErrInvalidCluster)
+	}
+
+	addrs := make([]string, 0, len(addresses))
+
+	for _, a := range addresses {
+		addr, err := entity.NormalizeAddress(a)
+		if err != nil {
+			return fmt.Errorf("ClusterStore - SetCluster - entity.NormalizeAddress: %w", err)
+		}
+
+		addrs = append(addrs, addr)
+	}
+
+	s.mu.Lock()
+	s.fromAPI[entityID] = addrs
+	s.reindex()
+	s.mu.Unlock()
+
+	s.notify()
+
+	return nil
+}
+
+// Deleting entity which was set through api.
+func (s *Store) DeleteCluster(entityID string) error {
+	s.mu.Lock()
+
+	if _, ok := s.fromAPI[entityID]; !ok {
+		s.mu.Unlock()
+
+		return fmt.Errorf("ClusterStore - DeleteCluster - %q: %w", entityID, entity.ErrClusterNotFound)
+	}
+
+	delete(s.fromAPI, entityID)
+	s.reindex()
+	s.mu.Unlock()
+
+	s.notify()
+
+	return nil
+}
+
+// Adding listener which is called after clusters are changed, listeners must be added before changes.
+func (s *Store) OnChange(listener func()) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// Calling listeners of changes, it's done without lock, so listeners can read clusters.
+func (s *Store) notify() {
+	for _, listener := range s.listeners {
+		listener()
+	}
+}
+
+// Building index of entities by address, clusters set through api are applied last.
+// Entities are applied in order of ids, so address owned by several entities always gets the same one.
+func (s *Store) reindex() {
+	s.entities = make(map[string]string)
+
+	for _, clusters := range []map[string][]string{s.fromFile, s.fromAPI} {
+		ids := make([]string, 0, len(clusters))
+		for id := range clusters {
+			ids = append(ids, id)
+		}
+
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			for _, addr := range clusters[id] {
+				s.entities[addr] = id
+			}
+		}
+	}
+}
+
+// CSV file contains columns address and entity.
+func clusterFromRow(row []string) (*clusterRecord, error) {
+	if len(row) != 2 {
+		return nil, entity.ErrInvalidCluster
+	}
+
+	return &clusterRecord{Address: row[0], Entity: row[1]}, nil
+}
@@ -0,0 +1,138 @@
+package clusters
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/go-playground/assert"
+)
+
+const (
+	testAddressA = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	testAddressB = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	testAddressC = "0xcccccccccccccccccccccccccccccccccccccccc"
+)
+
+// Writing clusters file with name in temp directory.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func Test_Reload(t *testing.T) {
+	for _, test := range testsReload {
+		t.Run(test.name, func(t *testing.T) {
+			s := New(writeFile(t, test.fileName, test.content))
+
+			err := s.Reload()
+
+			assert.Equal(t, errors.Is(err, test.expectedError), true)
+			assert.Equal(t, test.expectedClusters, s.GetClusters())
+		})
+	}
+}
+
+var testsReload = []struct {
+	name             string
+	fileName         string
+	content          string
+	expectedClusters map[string][]string
+	expectedError    error
+}{
+	{
+		name:     "YAML",
+		fileName: "clusters.yaml",
+		content: `
+- address: "` + testAddressB + `"
+  entity: "binance"
+- address: "0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+  entity: "binance"
+- address: "` + testAddressC + `"
+  entity: "coinbase"
+`,
+		expectedClusters: map[string][]string{
+			"binance":  {testAddressA, testAddressB},
+			"coinbase": {testAddressC},
+		},
+		expectedError: nil,
+	},
+	{
+		name:     "CSV with header",
+		fileName: "clusters.csv",
+		content: "address,entity\n" +
+			"# hot wallets\n" +
+			testAddressA + ",binance\n",
+		expectedClusters: map[string][]string{
+			"binance": {testAddressA},
+		},
+		expectedError: nil,
+	},
+	{
+		name:             "Empty file",
+		fileName:         "clusters.yml",
+		content:          "",
+		expectedClusters: map[string][]string{},
+		expectedError:    nil,
+	},
+	{
+		name:             "Invalid address",
+		fileName:         "clusters.csv",
+		content:          "0x123,binance\n",
+		expectedClusters: map[string][]string{},
+		expectedError:    entity.ErrInvalidCluster,
+	},
+	{
+		name:             "Unknown format",
+		fileName:         "clusters.json",
+		content:          "[]",
+		expectedClusters: map[string][]string{},
+		expectedError:    ErrUnknownFormat,
+	},
+}
+
+func Test_SetCluster(t *testing.T) {
+	s := New(writeFile(t, "clusters.csv", testAddressA+",binance\n"+testAddressB+",binance\n"))
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	var changes int
+
+	s.OnChange(func() { changes++ })
+	// Address set through api is moved to new entity
+	assert.Equal(t, nil, s.SetCluster(" coinbase ", []string{"0xBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB", testAddressC}))
+	assert.Equal(t, "binance", s.Entity(testAddressA))
+	assert.Equal(t, "coinbase", s.Entity(testAddressB))
+	assert.Equal(t, map[string][]string{
+		"binance":  {testAddressA},
+		"coinbase": {testAddressB, testAddressC},
+	}, s.GetClusters())
+	// Clusters set through api are kept on reload
+	assert.Equal(t, nil, s.Reload())
+	assert.Equal(t, "coinbase", s.Entity(testAddressB))
+
+	assert.Equal(t, nil, s.DeleteCluster("coinbase"))
+	assert.Equal(t, "binance", s.Entity(testAddressB))
+	assert.Equal(t, "", s.Entity(testAddressC))
+	// Listeners are called on every change
+	assert.Equal(t, 3, changes)
+}
+
+func Test_SetCluster_Errors(t *testing.T) {
+	s := New("")
+	s.OnChange(func() { t.Fatal("store isn't changed") })
+
+	assert.Equal(t, errors.Is(s.SetCluster("", []string{testAddressA}), entity.ErrInvalidCluster), true)
+	assert.Equal(t, errors.Is(s.SetCluster("binance", nil), entity.ErrInvalidCluster), true)
+	assert.Equal(t, errors.Is(s.SetCluster("binance", []string{"0x123"}), entity.ErrInvalidAddress), true)
+	assert.Equal(t, errors.Is(s.DeleteCluster("binance"), entity.ErrClusterNotFound), true)
+}
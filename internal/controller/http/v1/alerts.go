@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/egor-denisov/biggest-change/internal/usecase"
+	sl "github.com/egor-denisov/biggest-change/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+type alertsRoutes struct {
+	ar usecase.AlertRegistry
+	l  *slog.Logger
+}
+
+func newAlerts(handler *gin.RouterGroup, l *slog.Logger, ar usecase.AlertRegistry, token string) {
+	r := &alertsRoutes{ar, l}
+
+	h := handler.Group("/admin", adminAuth(token))
+	{
+		h.GET("/alerts", r.getRules)
+		h.PUT("/alerts/:id", r.setRule)
+		h.DELETE("/alerts/:id", r.deleteRule)
+	}
+}
+
+type alertRulesResponse struct {
+	Rules []entity.AlertRule `json:"rules"`
+}
+
+// @Summary     Получение правил оповещений
+// @Description Получение правил из конфига и правил, заданных через api
+// @Tags  	    Admin
+// @Security    AdminToken
+// @Success     200 {object} alertRulesResponse "Правила получены"
+// @Failure     401 "Неверный токен"
+// @Router      /admin/alerts [get] .
+func (r *alertsRoutes) getRules(c *gin.Context) {
+	c.JSON(http.StatusOK, alertRulesResponse{Rules: r.ar.GetRules()})
+}
+
+type setAlertRuleRequest struct {
+	Threshold     string `binding:"required" json:"threshold"`
+	Direction     string `json:"direction"`
+	CountOfBlocks uint   `json:"countOfBlocks"`
+	Address       string `json:"address"`
+	Category      string `json:"category"`
+	Webhook       string `binding:"required" json:"webhook"`
+}
+
+// @Summary     Задание правила оповещения
+// @Description Задание правила, правило с тем же id заменяется
+// @Description threshold - порог изменения в wei (десятичное или шестнадцатеричное число)
+// @Description direction - направление изменения: in, out или any, по умолчанию any
+// @Tags  	    Admin
+// @Security    AdminToken
+// @Param id path string true "Идентификатор правила"
+// @Param request body setAlertRuleRequest true "Правило"
+// @Success     204 "Правило задано"
+// @Failure     400 "Ошибка в запросе"
+// @Failure     401 "Неверный токен"
+// @Router      /admin/alerts/{id} [put] .
+func (r *alertsRoutes) setRule(c *gin.Context) {
+	var input setAlertRuleRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		r.l.Error("http - v1 - setRule", sl.Err(err))
+		c.AbortWithStatus(http.StatusBadRequest)
+
+		return
+	}
+
+	err := r.ar.SetRule(entity.AlertRule{
+		ID:            c.Param("id"),
+		Threshold:     input.Threshold,
+		Direction:     input.Direction,
+		CountOfBlocks: input.CountOfBlocks,
+		Address:       input.Address,
+		Category:      input.Category,
+		Webhook:       input.Webhook,
+	})
+	if err != nil {
+		if entity.RequestError(err) != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+
+			return
+		}
+
+		r.l.Error("http - v1 - setRule", sl.Err(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary     Удаление правила оповещения
+// @Description Удаление правила, заданного через api, правило из конфига с тем же id восстанавливается
+// @Tags  	    Admin
+// @Security    AdminToken
+// @Param id path string true "Идентификатор правила"
+// @Success     204 "Правило удалено"
+// @Failure     401 "Неверный токен"
+// @Failure     404 "Правило не найдено"
+// @Router      /admin/alerts/{id} [delete] .
+func (r *alertsRoutes) deleteRule(c *gin.Context) {
+	if err := r.ar.DeleteRule(c.Param("id")); err != nil {
+		if errors.Is(err, entity.ErrAlertRuleNotFound) {
+			c.AbortWithStatus(http.StatusNotFound)
+
+			return
+		}
+
+		r.l.Error("http - v1 - deleteRule", sl.Err(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	mock "github.com/egor-denisov/biggest-change/internal/usecase/mocks"
+	"github.com/egor-denisov/biggest-change/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_alerts(t *testing.T) {
+	for _, test := range testsAlerts {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			registry := mock.NewMockAlertRegistry(c)
+			test.mockBehavior(registry)
+
+			// Init Endpoint
+			r := gin.New()
+			newAlerts(r.Group("/"), logger.SetupLogger("debug"), registry, testAdminToken)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.requestBody))
+			req.Header.Set("Authorization", test.authorization)
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+var testRule = entity.AlertRule{
+	ID:        "whales",
+	Threshold: "1000000000000000000000",
+	Direction: entity.DirectionIn,
+	Webhook:   "https://example.com/hook",
+}
+
+var testsAlerts = []struct {
+	name                 string
+	method               string
+	path                 string
+	authorization        string
+	requestBody          string
+	mockBehavior         func(m *mock.MockAlertRegistry)
+	expectedStatusCode   int
+	expectedResponseBody string
+}{
+	{
+		name:          "get rules",
+		method:        http.MethodGet,
+		path:          "/admin/alerts",
+		authorization: "Bearer " + testAdminToken,
+		mockBehavior: func(m *mock.MockAlertRegistry) {
+			m.EXPECT().GetRules().Return([]entity.AlertRule{testRule})
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"rules":[{"id":"whales","threshold":"1000000000000000000000","direction":"in",` +
+			`"webhook":"https://example.com/hook"}]}`,
+	},
+	{
+		name:                 "invalid token",
+		method:               http.MethodGet,
+		path:                 "/admin/alerts",
+		authorization:        "",
+		mockBehavior:         func(_ *mock.MockAlertRegistry) {},
+		expectedStatusCode:   http.StatusUnauthorized,
+		expectedResponseBody: ``,
+	},
+	{
+		name:          "set rule",
+		method:        http.MethodPut,
+		path:          "/admin/alerts/whales",
+		authorization: "Bearer " + testAdminToken,
+		requestBody:   `{"threshold":"1000000000000000000000","direction":"in","webhook":"https://example.com/hook"}`,
+		mockBehavior: func(m *mock.MockAlertRegistry) {
+			m.EXPECT().SetRule(testRule).Return(nil)
+		},
+		expectedStatusCode:   http.StatusNoContent,
+		expectedResponseBody: ``,
+	},
+	{
+		name:          "set invalid rule",
+		method:        http.MethodPut,
+		path:          "/admin/alerts/whales",
+		authorization: "Bearer " + testAdminToken,
+		requestBody:   `{"threshold":"-1","webhook":"https://example.com/hook"}`,
+		mockBehavior: func(m *mock.MockAlertRegistry) {
+			m.EXPECT().SetRule(entity.AlertRule{ID: "whales", Threshold: "-1", Webhook: "https://example.com/hook"}).
+				Return(entity.ErrInvalidAlertRule)
+		},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:                 "set rule without webhook",
+		method:               http.MethodPut,
+		path:                 "/admin/alerts/whales",
+		authorization:        "Bearer " + testAdminToken,
+		requestBody:          `{"threshold":"1"}`,
+		mockBehavior:         func(_ *mock.MockAlertRegistry) {},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:          "delete rule",
+		method:        http.MethodDelete,
+		path:          "/admin/alerts/whales",
+		authorization: "Bearer " + testAdminToken,
+		mockBehavior: func(m *mock.MockAlertRegistry) {
+			m.EXPECT().DeleteRule("whales").Return(nil)
+		},
+		expectedStatusCode:   http.StatusNoContent,
+		expectedResponseBody: ``,
+	},
+	{
+		name:          "delete unknown rule",
+		method:        http.MethodDelete,
+		path:          "/admin/alerts/unknown",
+		authorization: "Bearer " + testAdminToken,
+		mockBehavior: func(m *mock.MockAlertRegistry) {
+			m.EXPECT().DeleteRule("unknown").Return(entity.ErrAlertRuleNotFound)
+		},
+		expectedStatusCode:   http.StatusNotFound,
+		expectedResponseBody: ``,
+	},
+}
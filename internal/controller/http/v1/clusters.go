@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/egor-denisov/biggest-change/internal/usecase"
+	sl "github.com/egor-denisov/biggest-change/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+type clustersRoutes struct {
+	cr usecase.ClusterRegistry
+	l  *slog.Logger
+}
+
+func newClusters(handler *gin.RouterGroup, l *slog.Logger, cr usecase.ClusterRegistry, token string) {
+	r := &clustersRoutes{cr, l}
+
+	h := handler.Group("/admin", adminAuth(token))
+	{
+		h.GET("/clusters", r.getClusters)
+		h.PUT("/clusters/:entity", r.setCluster)
+		h.DELETE("/clusters/:entity", r.deleteCluster)
+	}
+}
+
+// Checking that request has bearer token of admin.
+func adminAuth(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+type clustersResponse struct {
+	Clusters map[string][]string `json:"clusters"`
+}
+
+// @Summary     Получение кластеров адресов
+// @Description Получение всех сущностей и их адресов
+// @Tags  	    Admin
+// @Security    AdminToken
+// @Success     200 {object} clustersResponse "Кластеры получены"
+// @Failure     401 "Неверный токен"
+// @Router      /admin/clusters [get] .
+func (r *clustersRoutes) getClusters(c *gin.Context) {
+	c.JSON(http.StatusOK, clustersResponse{Clusters: r.cr.GetClusters()})
+}
+
+type setClusterRequest struct {
+	Addresses []string `binding:"required" json:"addresses"`
+}
+
+// @Summary     Задание кластера адресов
+// @Description Задание адресов сущности, адреса переходят к ней от других сущностей
+// @Description Кластеры, заданные через api, сохраняются при перечитывании файла
+// @Tags  	    Admin
+// @Security    AdminToken
+// @Param entity path string true "Идентификатор сущности"
+// @Param request body setClusterRequest true "Адреса сущности"
+// @Success     204 "Кластер задан"
+// @Failure     400 "Ошибка в запросе"
+// @Failure     401 "Неверный токен"
+// @Router      /admin/clusters/{entity} [put] .
+func (r *clustersRoutes) setCluster(c *gin.Context) {
+	var input setClusterRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		r.l.Error("http - v1 - setCluster", sl.Err(err))
+		c.AbortWithStatus(http.StatusBadRequest)
+
+		return
+	}
+
+	if err := r.cr.SetCluster(c.Param("entity"), input.Addresses); err != nil {
+		if entity.RequestError(err) != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+
+			return
+		}
+
+		r.l.Error("http - v1 - setCluster", sl.Err(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary     Удаление кластера адресов
+// @Description Удаление сущности, заданной через api
+// @Tags  	    Admin
+// @Security    AdminToken
+// @Param entity path string true "Идентификатор сущности"
+// @Success     204 "Кластер удалён"
+// @Failure     401 "Неверный токен"
+// @Failure     404 "Кластер не найден"
+// @Router      /admin/clusters/{entity} [delete] .
+func (r *clustersRoutes) deleteCluster(c *gin.Context) {
+	if err := r.cr.DeleteCluster(c.Param("entity")); err != nil {
+		if errors.Is(err, entity.ErrClusterNotFound) {
+			c.AbortWithStatus(http.StatusNotFound)
+
+			return
+		}
+
+		r.l.Error("http - v1 - deleteCluster", sl.Err(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
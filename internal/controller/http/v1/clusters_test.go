@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	mock "github.com/egor-denisov/biggest-change/internal/usecase/mocks"
+	"github.com/egor-denisov/biggest-change/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert"
+	"github.com/golang/mock/gomock"
+)
+
+const testAdminToken = "secret"
+
+func Test_clusters(t *testing.T) {
+	for _, test := range testsClusters {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			registry := mock.NewMockClusterRegistry(c)
+			test.mockBehavior(registry)
+
+			// Init Endpoint
+			r := gin.New()
+			newClusters(r.Group("/"), logger.SetupLogger("debug"), registry, testAdminToken)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.requestBody))
+			req.Header.Set("Authorization", test.authorization)
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+var testsClusters = []struct {
+	name                 string
+	method               string
+	path                 string
+	authorization        string
+	requestBody          string
+	mockBehavior         func(m *mock.MockClusterRegistry)
+	expectedStatusCode   int
+	expectedResponseBody string
+}{
+	{
+		name:          "get clusters",
+		method:        http.MethodGet,
+		path:          "/admin/clusters",
+		authorization: "Bearer " + testAdminToken,
+		mockBehavior: func(m *mock.MockClusterRegistry) {
+			m.EXPECT().GetClusters().Return(map[string][]string{"binance": {testAddressA, testAddressB}})
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"clusters":{"binance":[` +
+			`"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa","0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"]}}`,
+	},
+	{
+		name:                 "invalid token",
+		method:               http.MethodGet,
+		path:                 "/admin/clusters",
+		authorization:        "Bearer wrong",
+		mockBehavior:         func(_ *mock.MockClusterRegistry) {},
+		expectedStatusCode:   http.StatusUnauthorized,
+		expectedResponseBody: ``,
+	},
+	{
+		name:          "set cluster",
+		method:        http.MethodPut,
+		path:          "/admin/clusters/binance",
+		authorization: "Bearer " + testAdminToken,
+		requestBody:   fmt.Sprintf(`{"addresses":[%q]}`, testAddressA),
+		mockBehavior: func(m *mock.MockClusterRegistry) {
+			m.EXPECT().SetCluster("binance", []string{testAddressA}).Return(nil)
+		},
+		expectedStatusCode:   http.StatusNoContent,
+		expectedResponseBody: ``,
+	},
+	{
+		name:          "set cluster with invalid address",
+		method:        http.MethodPut,
+		path:          "/admin/clusters/binance",
+		authorization: "Bearer " + testAdminToken,
+		requestBody:   `{"addresses":["0x123"]}`,
+		mockBehavior: func(m *mock.MockClusterRegistry) {
+			m.EXPECT().SetCluster("binance", []string{"0x123"}).Return(entity.ErrInvalidAddress)
+		},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:                 "set cluster without addresses",
+		method:               http.MethodPut,
+		path:                 "/admin/clusters/binance",
+		authorization:        "Bearer " + testAdminToken,
+		requestBody:          `{}`,
+		mockBehavior:         func(_ *mock.MockClusterRegistry) {},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:          "delete cluster",
+		method:        http.MethodDelete,
+		path:          "/admin/clusters/binance",
+		authorization: "Bearer " + testAdminToken,
+		mockBehavior: func(m *mock.MockClusterRegistry) {
+			m.EXPECT().DeleteCluster("binance").Return(nil)
+		},
+		expectedStatusCode:   http.StatusNoContent,
+		expectedResponseBody: ``,
+	},
+	{
+		name:          "delete unknown cluster",
+		method:        http.MethodDelete,
+		path:          "/admin/clusters/coinbase",
+		authorization: "Bearer " + testAdminToken,
+		mockBehavior: func(m *mock.MockClusterRegistry) {
+			m.EXPECT().DeleteCluster("coinbase").Return(entity.ErrClusterNotFound)
+		},
+		expectedStatusCode:   http.StatusNotFound,
+		expectedResponseBody: ``,
+	},
+	{
+		name:          "something went wrong",
+		method:        http.MethodDelete,
+		path:          "/admin/clusters/binance",
+		authorization: "Bearer " + testAdminToken,
+		mockBehavior: func(m *mock.MockClusterRegistry) {
+			m.EXPECT().DeleteCluster("binance").Return(errSomethingWentWrong)
+		},
+		expectedStatusCode:   http.StatusInternalServerError,
+		expectedResponseBody: ``,
+	},
+}
@@ -0,0 +1,26 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration which is passed in json as string like "1h30m".
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration - UnmarshalJSON - json.Unmarshal: %w", err)
+	}
+
+	res, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("duration - UnmarshalJSON - time.ParseDuration: %w", err)
+	}
+
+	*d = duration(res)
+
+	return nil
+}
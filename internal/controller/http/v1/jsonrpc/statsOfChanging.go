@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/egor-denisov/biggest-change/internal/entity"
 	"github.com/egor-denisov/biggest-change/internal/usecase"
@@ -29,12 +30,16 @@ func (s *StatsOfChangingService) GetBiggestChange(
 	args *GetBiggestChangeArgs,
 	result *GetBiggestChangeResult,
 ) error {
-	res, err := s.sc.GetAddressWithBiggestChange(r.Context(), args.CountOfBlocks)
+	res, err := s.sc.GetAddressWithBiggestChange(r.Context(), args.query())
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return entity.ErrProcessTimeout
 		}
 
+		if reqErr := entity.RequestError(err); reqErr != nil {
+			return reqErr
+		}
+
 		s.l.Error("jsonrpc - GetBiggestChange", sl.Err(err))
 
 		return entity.ErrInternalServer
@@ -46,7 +51,158 @@ func (s *StatsOfChangingService) GetBiggestChange(
 }
 
 type GetBiggestChangeArgs struct {
-	CountOfBlocks uint `json:"countOfBlocks"`
+	CountOfBlocks     uint       `json:"countOfBlocks"`
+	FromBlock         *uint64    `json:"fromBlock"`
+	ToBlock           *uint64    `json:"toBlock"`
+	Token             string     `json:"token"`
+	Anchor            string     `json:"anchor"`
+	Since             *time.Time `json:"since"`
+	Duration          duration   `json:"duration"`
+	Metric            string     `json:"metric"`
+	Explain           bool       `json:"explain"`
+	Include           []string   `json:"include"`
+	Exclude           []string   `json:"exclude"`
+	Categories        []string   `json:"categories"`
+	ExcludeCategories []string   `json:"excludeCategories"`
+	GroupBy           string     `json:"groupBy"`
+	Balances          bool       `json:"balances"`
+}
+
+func (a *GetBiggestChangeArgs) query() entity.ChangesQuery {
+	return entity.ChangesQuery{
+		CountOfBlocks:     a.CountOfBlocks,
+		FromBlock:         a.FromBlock,
+		ToBlock:           a.ToBlock,
+		Token:             a.Token,
+		Anchor:            a.Anchor,
+		Since:             a.Since,
+		Duration:          time.Duration(a.Duration),
+		Metric:            a.Metric,
+		Explain:           a.Explain,
+		Include:           a.Include,
+		Exclude:           a.Exclude,
+		Categories:        a.Categories,
+		ExcludeCategories: a.ExcludeCategories,
+		GroupBy:           a.GroupBy,
+		Balances:          a.Balances,
+	}
 }
 
 type GetBiggestChangeResult *entity.BiggestChange
+
+// Getting top changes for json rpc endpoint.
+func (s *StatsOfChangingService) GetTopChanges(
+	r *http.Request,
+	args *GetTopChangesArgs,
+	result *GetTopChangesResult,
+) error {
+	res, err := s.sc.GetTopChanges(r.Context(), args.query(), args.Limit)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return entity.ErrProcessTimeout
+		}
+
+		if reqErr := entity.RequestError(err); reqErr != nil {
+			return reqErr
+		}
+
+		s.l.Error("jsonrpc - GetTopChanges", sl.Err(err))
+
+		return entity.ErrInternalServer
+	}
+
+	*result = res
+
+	return nil
+}
+
+type GetTopChangesArgs struct {
+	GetBiggestChangeArgs
+	Limit uint `json:"limit"`
+}
+
+type GetTopChangesResult *entity.TopChanges
+
+// Getting last block at or before time for json rpc endpoint.
+func (s *StatsOfChangingService) GetBlockAt(
+	r *http.Request,
+	args *GetBlockAtArgs,
+	result *GetBlockAtResult,
+) error {
+	res, err := s.sc.GetBlockAtTime(r.Context(), args.Time)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return entity.ErrProcessTimeout
+		}
+
+		if reqErr := entity.RequestError(err); reqErr != nil {
+			return reqErr
+		}
+
+		s.l.Error("jsonrpc - GetBlockAt", sl.Err(err))
+
+		return entity.ErrInternalServer
+	}
+
+	*result = res
+
+	return nil
+}
+
+type GetBlockAtArgs struct {
+	Time time.Time `json:"time"`
+}
+
+type GetBlockAtResult *entity.BlockAtTime
+
+// Getting changes of address in each block for json rpc endpoint.
+func (s *StatsOfChangingService) GetAddressChanges(
+	r *http.Request,
+	args *GetAddressChangesArgs,
+	result *GetAddressChangesResult,
+) error {
+	res, err := s.sc.GetAddressChanges(r.Context(), args.Address, args.query())
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return entity.ErrProcessTimeout
+		}
+
+		if reqErr := entity.RequestError(err); reqErr != nil {
+			return reqErr
+		}
+
+		s.l.Error("jsonrpc - GetAddressChanges", sl.Err(err))
+
+		return entity.ErrInternalServer
+	}
+
+	*result = res
+
+	return nil
+}
+
+// History of address has no ranking, so only window is described by arguments.
+type GetAddressChangesArgs struct {
+	Address       string     `json:"address"`
+	CountOfBlocks uint       `json:"countOfBlocks"`
+	FromBlock     *uint64    `json:"fromBlock"`
+	ToBlock       *uint64    `json:"toBlock"`
+	Token         string     `json:"token"`
+	Anchor        string     `json:"anchor"`
+	Since         *time.Time `json:"since"`
+	Duration      duration   `json:"duration"`
+}
+
+func (a *GetAddressChangesArgs) query() entity.ChangesQuery {
+	return entity.ChangesQuery{
+		CountOfBlocks: a.CountOfBlocks,
+		FromBlock:     a.FromBlock,
+		ToBlock:       a.ToBlock,
+		Token:         a.Token,
+		Anchor:        a.Anchor,
+		Since:         a.Since,
+		Duration:      time.Duration(a.Duration),
+	}
+}
+
+type GetAddressChangesResult *entity.AddressChanges
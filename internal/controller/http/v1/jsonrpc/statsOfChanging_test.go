@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/rpc"
 	"github.com/gorilla/rpc/json"
@@ -92,14 +93,22 @@ var testsGetBiggestChange = []struct {
 			result := &entity.BiggestChange{
 				Address:       "0x1",
 				Amount:        "0x100",
+				FirstBlock:    "0xf2",
 				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
 				CountOfBlocks: 50,
 				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
 			}
-			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), uint(50)).Return(result, nil)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 50}).Return(result, nil)
 		},
-		expectedResponseBody: `{"result":{"address":"0x1","amount":"0x100","lastBlock":"0x123",` +
-			`"countOfBlocks":50,"isRecieved":true},"error":null,"id":"1"}`,
+		expectedResponseBody: `{"result":{"address":"0x1","amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123"},` +
+			`"error":null,"id":"1"}`,
 	},
 	{
 		name:        "Default Count Of Blocks",
@@ -108,20 +117,184 @@ var testsGetBiggestChange = []struct {
 			result := &entity.BiggestChange{
 				Address:       "0x1",
 				Amount:        "0x100",
+				FirstBlock:    "0xf2",
 				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
 				CountOfBlocks: int64(100),
 				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
 			}
-			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), uint(0)).Return(result, nil)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{}).Return(result, nil)
 		},
-		expectedResponseBody: `{"result":{"address":"0x1","amount":"0x100","lastBlock":"0x123",` +
-			`"countOfBlocks":100,"isRecieved":true},"error":null,"id":"1"}`,
+		expectedResponseBody: `{"result":{"address":"0x1","amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":100,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123"},` +
+			`"error":null,"id":"1"}`,
+	},
+	{
+		name: "Block Range",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetBiggestChange",` +
+			`"params": [{"fromBlock": 242, "toBlock": 291}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			result := &entity.BiggestChange{
+				Address:       "0x1",
+				Amount:        "0x100",
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			from, to := uint64(242), uint64(291)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{FromBlock: &from, ToBlock: &to}).
+				Return(result, nil)
+		},
+		expectedResponseBody: `{"result":{"address":"0x1","amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123"},` +
+			`"error":null,"id":"1"}`,
+	},
+	{
+		name: "Time Window",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetBiggestChange",` +
+			`"params": [{"since": "2024-04-01T00:00:00Z", "duration": "1h"}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			result := &entity.BiggestChange{
+				Address:        "0x1",
+				Amount:         "0x100",
+				FirstBlock:     "0xf2",
+				LastBlock:      "0x123",
+				FirstBlockTime: 1711929607,
+				LastBlockTime:  1711933199,
+				Metric:         entity.MetricNet,
+				CountOfBlocks:  50,
+				IsRecieved:     true,
+				BurnedFees:     "0x0",
+				Anchor:         "latest",
+				AnchorBlock:    "0x200",
+			}
+			since := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{Since: &since, Duration: time.Hour}).
+				Return(result, nil)
+		},
+		expectedResponseBody: `{"result":{"address":"0x1","amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":1711929607,"lastBlockTimestamp":1711933199,` +
+			`"metric":"net","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x200"},"error":null,"id":"1"}`,
+	},
+	{
+		name: "Explain",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetBiggestChange",` +
+			`"params": [{"countOfBlocks": 50, "explain": true}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			result := &entity.BiggestChange{
+				Address:       "0x1",
+				Amount:        "0x100",
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+				Transactions: []*entity.ContributingTransaction{
+					{Hash: "0xa", Block: "0x123", Value: "0x100", Fee: "0x0", IsRecieved: true},
+				},
+			}
+			query := entity.ChangesQuery{CountOfBlocks: 50, Explain: true}
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), query).Return(result, nil)
+		},
+		expectedResponseBody: `{"result":{"address":"0x1","amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123",` +
+			`"transactions":[{"hash":"0xa","block":"0x123","value":"0x100","fee":"0x0","isRecieved":true}]},` +
+			`"error":null,"id":"1"}`,
+	},
+	{
+		name: "Label And Excluded Category",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetBiggestChange",` +
+			`"params": [{"countOfBlocks": 50, "excludeCategories": ["bridge"]}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			result := &entity.BiggestChange{
+				Address:       "0x1",
+				Label:         &entity.Label{Name: "Binance 14", Category: "exchange"},
+				Amount:        "0x100",
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+				Filters:       &entity.AddressFilters{ExcludeCategories: []string{"bridge"}},
+			}
+			query := entity.ChangesQuery{CountOfBlocks: 50, ExcludeCategories: []string{"bridge"}}
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), query).Return(result, nil)
+		},
+		expectedResponseBody: `{"result":{"address":"0x1","label":{"name":"Binance 14","category":"exchange"},` +
+			`"amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123",` +
+			`"filters":{"excludeCategories":["bridge"]}},` +
+			`"error":null,"id":"1"}`,
+	},
+	{
+		name: "Group By Entity",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetBiggestChange",` +
+			`"params": [{"countOfBlocks": 50, "groupBy": "entity"}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			result := &entity.BiggestChange{
+				Address:       "binance",
+				Entity:        "binance",
+				Addresses:     []string{"0x1", "0x2"},
+				Amount:        "0x100",
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				GroupBy:       entity.GroupByEntity,
+				CountOfBlocks: 50,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			query := entity.ChangesQuery{CountOfBlocks: 50, GroupBy: entity.GroupByEntity}
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), query).Return(result, nil)
+		},
+		expectedResponseBody: `{"result":{"address":"binance","entity":"binance","addresses":["0x1","0x2"],` +
+			`"amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","groupBy":"entity","countOfBlocks":50,"isRecieved":false,"isNewContract":false,` +
+			`"failedTransactions":0,"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123"},` +
+			`"error":null,"id":"1"}`,
+	},
+	{
+		name: "Invalid Block Range",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetBiggestChange",` +
+			`"params": [{"fromBlock": 300, "toBlock": 291}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			from, to := uint64(300), uint64(291)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{FromBlock: &from, ToBlock: &to}).
+				Return(nil, entity.ErrInvalidBlockRange)
+		},
+		expectedResponseBody: `{"result":null,"error":"invalid block range","id":"1"}`,
 	},
 	{
 		name:        "Timeout Error Handling",
 		requestBody: getBodyRequestByCountOfBlock(10),
 		mockBehavior: func(m *mock.MockStatsOfChanging) {
-			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), uint(10)).Return(nil, context.DeadlineExceeded)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 10}).
+				Return(nil, context.DeadlineExceeded)
 		},
 		expectedResponseBody: `{"result":null,"error":"process timeout","id":"1"}`,
 	},
@@ -129,8 +302,341 @@ var testsGetBiggestChange = []struct {
 		name:        "Else Error Handling",
 		requestBody: getBodyRequestByCountOfBlock(10),
 		mockBehavior: func(m *mock.MockStatsOfChanging) {
-			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), uint(10)).Return(nil, entity.ErrInternalServer)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 10}).
+				Return(nil, entity.ErrInternalServer)
+		},
+		expectedResponseBody: `{"result":null,"error":"internal server error","id":"1"}`,
+	},
+}
+
+func Test_GetTopChanges(t *testing.T) {
+	for _, test := range testsGetTopChanges {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			webapi := mock.NewMockStatsOfChanging(c)
+			test.mockBehavior(webapi)
+
+			rpcServer := rpc.NewServer()
+			rpcServer.RegisterCodec(json.NewCodec(), "application/json")
+
+			s := NewStatsOfChangingService(logger.SetupLogger("debug"), webapi)
+			_ = rpcServer.RegisterService(s, "JsonRpc")
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/", gin.WrapH(rpcServer))
+
+			// Create Request
+			req, err := http.NewRequestWithContext(
+				context.Background(),
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(test.requestBody),
+			)
+			assert.Equal(t, err, nil)
+
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, strings.TrimSpace(w.Body.String()), strings.TrimSpace(test.expectedResponseBody))
+		})
+	}
+}
+
+func getTopChangesRequestBody(countOfBlocks, limit int) string {
+	return fmt.Sprintf(
+		`
+		{
+			"id": "1",
+			"jsonrpc": "2.0",
+			"method": "JsonRpc.GetTopChanges",
+			"params": [{
+				"countOfBlocks": %d,
+				"limit": %d
+			}]
+		}
+		`, countOfBlocks, limit,
+	)
+}
+
+var testsGetTopChanges = []struct {
+	name                 string
+	requestBody          string
+	mockBehavior         mockBehavior
+	expectedResponseBody string
+}{
+	{
+		name:        "Success",
+		requestBody: getTopChangesRequestBody(50, 1),
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			result := &entity.TopChanges{
+				Changes:       []*entity.AddressChange{{Address: "0x1", Amount: "0x100", IsRecieved: true}},
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 50}, uint(1)).Return(result, nil)
+		},
+		expectedResponseBody: `{"result":{"changes":[{"address":"0x1","amount":"0x100","isRecieved":true,` +
+			`"isNewContract":false}],` +
+			`"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"failedTransactions":0,"burnedFees":"0x0",` +
+			`"anchor":"latest","anchorBlock":"0x123"},` +
+			`"error":null,"id":"1"}`,
+	},
+	{
+		name: "Outflow Metric",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetTopChanges",` +
+			`"params": [{"countOfBlocks": 50, "limit": 1, "metric": "outflow"}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			result := &entity.TopChanges{
+				Changes:       []*entity.AddressChange{{Address: "0x1", Amount: "0x100", IsRecieved: false}},
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricOutflow,
+				CountOfBlocks: 50,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			query := entity.ChangesQuery{CountOfBlocks: 50, Metric: entity.MetricOutflow}
+			m.EXPECT().GetTopChanges(gomock.Any(), query, uint(1)).Return(result, nil)
+		},
+		expectedResponseBody: `{"result":{"changes":[{"address":"0x1","amount":"0x100","isRecieved":false,` +
+			`"isNewContract":false}],` +
+			`"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"outflow","countOfBlocks":50,"failedTransactions":0,"burnedFees":"0x0",` +
+			`"anchor":"latest","anchorBlock":"0x123"},` +
+			`"error":null,"id":"1"}`,
+	},
+	{
+		name: "Filters",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetTopChanges",` +
+			`"params": [{"limit": 1, "exclude": ["0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"]}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			result := &entity.TopChanges{
+				Changes:       []*entity.AddressChange{{Address: "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Amount: "0x100"}},
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+				Filters: &entity.AddressFilters{
+					Exclude:          []string{"0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+					ExcludedByConfig: 3,
+				},
+			}
+			query := entity.ChangesQuery{Exclude: []string{"0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"}}
+			m.EXPECT().GetTopChanges(gomock.Any(), query, uint(1)).Return(result, nil)
+		},
+		expectedResponseBody: `{"result":{"changes":[` +
+			`{"address":"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa","amount":"0x100",` +
+			`"isRecieved":false,"isNewContract":false}],` +
+			`"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"failedTransactions":0,"burnedFees":"0x0",` +
+			`"anchor":"latest","anchorBlock":"0x123",` +
+			`"filters":{"exclude":["0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"],"excludedByConfig":3}},` +
+			`"error":null,"id":"1"}`,
+	},
+	{
+		name: "Invalid Metric",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetTopChanges",` +
+			`"params": [{"metric": "volume", "limit": 5}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{Metric: "volume"}, uint(5)).
+				Return(nil, entity.ErrInvalidMetric)
+		},
+		expectedResponseBody: `{"result":null,"error":"invalid metric","id":"1"}`,
+	},
+	{
+		name: "Invalid Token",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetTopChanges",` +
+			`"params": [{"token": "0x123", "limit": 5}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{Token: "0x123"}, uint(5)).
+				Return(nil, entity.ErrInvalidAddress)
+		},
+		expectedResponseBody: `{"result":null,"error":"invalid address","id":"1"}`,
+	},
+	{
+		name:        "Timeout Error Handling",
+		requestBody: getTopChangesRequestBody(10, 5),
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 10}, uint(5)).
+				Return(nil, context.DeadlineExceeded)
+		},
+		expectedResponseBody: `{"result":null,"error":"process timeout","id":"1"}`,
+	},
+	{
+		name:        "Else Error Handling",
+		requestBody: getTopChangesRequestBody(10, 5),
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 10}, uint(5)).
+				Return(nil, entity.ErrInternalServer)
 		},
 		expectedResponseBody: `{"result":null,"error":"internal server error","id":"1"}`,
 	},
 }
+
+func Test_GetBlockAt(t *testing.T) {
+	for _, test := range testsGetBlockAt {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			webapi := mock.NewMockStatsOfChanging(c)
+			test.mockBehavior(webapi)
+
+			rpcServer := rpc.NewServer()
+			rpcServer.RegisterCodec(json.NewCodec(), "application/json")
+
+			s := NewStatsOfChangingService(logger.SetupLogger("debug"), webapi)
+			_ = rpcServer.RegisterService(s, "JsonRpc")
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/", gin.WrapH(rpcServer))
+
+			// Create Request
+			req, err := http.NewRequestWithContext(
+				context.Background(),
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(test.requestBody),
+			)
+			assert.Equal(t, err, nil)
+
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, strings.TrimSpace(w.Body.String()), strings.TrimSpace(test.expectedResponseBody))
+		})
+	}
+}
+
+var testsGetBlockAt = []struct {
+	name                 string
+	requestBody          string
+	mockBehavior         mockBehavior
+	expectedResponseBody string
+}{
+	{
+		name: "Success",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetBlockAt",` +
+			`"params": [{"time": "2024-04-01T00:00:00Z"}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			result := &entity.BlockAtTime{Number: "0x12bba85", Hash: "0xh", Timestamp: 1711929599}
+			m.EXPECT().GetBlockAtTime(gomock.Any(), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)).Return(result, nil)
+		},
+		expectedResponseBody: `{"result":{"number":"0x12bba85","hash":"0xh","timestamp":1711929599},"error":null,"id":"1"}`,
+	},
+	{
+		name: "Time Before Genesis",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetBlockAt",` +
+			`"params": [{"time": "2000-01-01T00:00:00Z"}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetBlockAtTime(gomock.Any(), time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).
+				Return(nil, entity.ErrTimeBeforeGenesis)
+		},
+		expectedResponseBody: `{"result":null,"error":"time is before first block","id":"1"}`,
+	},
+}
+
+func Test_GetAddressChanges(t *testing.T) {
+	for _, test := range testsGetAddressChanges {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			webapi := mock.NewMockStatsOfChanging(c)
+			test.mockBehavior(webapi)
+
+			rpcServer := rpc.NewServer()
+			rpcServer.RegisterCodec(json.NewCodec(), "application/json")
+
+			s := NewStatsOfChangingService(logger.SetupLogger("debug"), webapi)
+			_ = rpcServer.RegisterService(s, "JsonRpc")
+
+			// Init Endpoint
+			r := gin.New()
+			r.POST("/", gin.WrapH(rpcServer))
+
+			// Create Request
+			req, err := http.NewRequestWithContext(
+				context.Background(),
+				http.MethodPost,
+				"/",
+				bytes.NewBufferString(test.requestBody),
+			)
+			assert.Equal(t, err, nil)
+
+			req.Header.Set("Content-Type", "application/json")
+
+			// Make Request
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, strings.TrimSpace(w.Body.String()), strings.TrimSpace(test.expectedResponseBody))
+		})
+	}
+}
+
+var testsGetAddressChanges = []struct {
+	name                 string
+	requestBody          string
+	mockBehavior         mockBehavior
+	expectedResponseBody string
+}{
+	{
+		name: "Success",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetAddressChanges",` +
+			`"params": [{"address": "0x1", "countOfBlocks": 50}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			result := &entity.AddressChanges{
+				Address:       "0x1",
+				Changes:       []*entity.BlockChange{{Block: "0xf3", Amount: "0x100", IsRecieved: true}},
+				Total:         "0x100",
+				IsRecieved:    true,
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				CountOfBlocks: 50,
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			m.EXPECT().GetAddressChanges(gomock.Any(), "0x1", entity.ChangesQuery{CountOfBlocks: 50}).Return(result, nil)
+		},
+		expectedResponseBody: `{"result":{"address":"0x1","changes":[{"block":"0xf3","amount":"0x100","isRecieved":true}],` +
+			`"total":"0x100","isRecieved":true,"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,` +
+			`"lastBlockTimestamp":0,"countOfBlocks":50,"anchor":"latest","anchorBlock":"0x123"},"error":null,"id":"1"}`,
+	},
+	{
+		name: "Invalid Address",
+		requestBody: `{"id": "1", "jsonrpc": "2.0", "method": "JsonRpc.GetAddressChanges",` +
+			`"params": [{"address": "0x1"}]}`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetAddressChanges(gomock.Any(), "0x1", entity.ChangesQuery{}).
+				Return(nil, entity.ErrInvalidAddress)
+		},
+		expectedResponseBody: `{"result":null,"error":"invalid address","id":"1"}`,
+	},
+}
@@ -9,6 +9,8 @@ import (
 
 	_ "github.com/egor-denisov/biggest-change/docs" //nolint:blank-imports //for working swagger documentation
 	"github.com/egor-denisov/biggest-change/internal/controller/http/v1/jsonrpc"
+	"github.com/egor-denisov/biggest-change/internal/controller/sse"
+	"github.com/egor-denisov/biggest-change/internal/controller/ws"
 	"github.com/egor-denisov/biggest-change/internal/usecase"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -21,8 +23,20 @@ import (
 // @version     1.0
 // @host        localhost:8080
 // @BasePath    /api/v1
+// @securityDefinitions.apikey AdminToken
+// @in          header
+// @name        Authorization
 // .
-func NewRouter(handler *gin.Engine, l *slog.Logger, sc usecase.StatsOfChanging) {
+func NewRouter(
+	handler *gin.Engine,
+	l *slog.Logger,
+	sc usecase.StatsOfChanging,
+	cr usecase.ClusterRegistry,
+	ar usecase.AlertRegistry,
+	hub *ws.Hub,
+	stream *sse.Stream,
+	adminToken string,
+) {
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
 
@@ -47,5 +61,12 @@ func NewRouter(handler *gin.Engine, l *slog.Logger, sc usecase.StatsOfChanging)
 	h := handler.Group("/api/v1")
 	{
 		newStatsOfChanging(h, l, sc)
+		newWS(h, hub)
+		newStream(h, stream)
+		// Admin api is disabled without token
+		if adminToken != "" {
+			newClusters(h, l, cr, adminToken)
+			newAlerts(h, l, ar, adminToken)
+		}
 	}
 }
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
+	"github.com/egor-denisov/biggest-change/internal/entity"
 	"github.com/egor-denisov/biggest-change/internal/usecase"
 	sl "github.com/egor-denisov/biggest-change/pkg/logger"
 	"github.com/gin-gonic/gin"
@@ -22,18 +24,72 @@ func newStatsOfChanging(handler *gin.RouterGroup, l *slog.Logger, sc usecase.Sta
 	h := handler.Group("/")
 	{
 		h.GET("/get_biggest_change", r.getBiggestChange)
+		h.GET("/top_changes", r.getTopChanges)
+		h.GET("/block_at", r.getBlockAt)
+		h.GET("/addresses/:address/changes", r.getAddressChanges)
 	}
 }
 
 type getBiggestChangeRequest struct {
-	CountOfBlocks uint `form:"count_of_blocks"`
+	CountOfBlocks     uint          `form:"count_of_blocks"`
+	FromBlock         *uint64       `form:"from_block"`
+	ToBlock           *uint64       `form:"to_block"`
+	Token             string        `form:"token"`
+	Anchor            string        `form:"anchor"`
+	Since             *time.Time    `form:"since"`
+	Duration          time.Duration `form:"duration"`
+	Metric            string        `form:"metric"`
+	Explain           bool          `form:"explain"`
+	Include           []string      `form:"include"`
+	Exclude           []string      `form:"exclude"`
+	Categories        []string      `form:"category"`
+	ExcludeCategories []string      `form:"exclude_category"`
+	GroupBy           string        `form:"group_by"`
+	Balances          bool          `form:"balances"`
+}
+
+func (r *getBiggestChangeRequest) query() entity.ChangesQuery {
+	return entity.ChangesQuery{
+		CountOfBlocks:     r.CountOfBlocks,
+		FromBlock:         r.FromBlock,
+		ToBlock:           r.ToBlock,
+		Token:             r.Token,
+		Anchor:            r.Anchor,
+		Since:             r.Since,
+		Duration:          r.Duration,
+		Metric:            r.Metric,
+		Explain:           r.Explain,
+		Include:           r.Include,
+		Exclude:           r.Exclude,
+		Categories:        r.Categories,
+		ExcludeCategories: r.ExcludeCategories,
+		GroupBy:           r.GroupBy,
+		Balances:          r.Balances,
+	}
 }
 
 // @Summary     Получение адреса, который максимально
 // @Description Получение адреса, который максимально изменился за count_of_blocks блоков
 // @Description По умолчанию count_of_blocks = 100
+// @Description Если заданы from_block и to_block, то используются блоки [from_block; to_block]
+// @Description Если задана только одна граница, то окно из count_of_blocks блоков строится от неё
+// @Description Если заданы since и/или duration, то окно строится по времени блоков
 // @Tags  	    StatsOfChanging
 // @Param count_of_blocks query integer false "Количество последних блоков"
+// @Param from_block query integer false "Первый блок"
+// @Param to_block query integer false "Последний блок"
+// @Param token query string false "Адрес ERC-20 токена, изменения считаются в базовых единицах токена"
+// @Param anchor query string false "Блок, на котором заканчивается окно: latest, safe или finalized"
+// @Param since query string false "Начало окна по времени блоков в формате RFC 3339"
+// @Param duration query string false "Длительность окна по времени блоков, например 1h30m"
+// @Param metric query string false "Метрика ранжирования: net, inflow, outflow, gross, tx_count или relative, по умолчанию net"
+// @Param include query []string false "Адреса, среди которых ищутся изменения" collectionFormat(multi)
+// @Param exclude query []string false "Адреса, которые не учитываются при поиске" collectionFormat(multi)
+// @Param category query []string false "Категории меток адресов, среди которых ищутся изменения" collectionFormat(multi)
+// @Param exclude_category query []string false "Категории меток адресов, которые не учитываются" collectionFormat(multi)
+// @Param group_by query string false "Группировка: address или entity, entity суммирует адреса одной сущности (только для net)"
+// @Param balances query boolean false "Вернуть балансы адресов до и после окна и изменение в процентах"
+// @Param explain query boolean false "Вернуть транзакции, которые сильнее всего изменили баланс адреса"
 // @Success     200 {object} entity.BiggestChange "Адрес найден"
 // @Failure     400 "Ошибка в запросе"
 // @Failure     500 "Не удалось выполнить запрос"
@@ -49,7 +105,7 @@ func (r *statsOfChangingRoutes) getBiggestChange(c *gin.Context) {
 		return
 	}
 
-	res, err := r.sc.GetAddressWithBiggestChange(c.Request.Context(), input.CountOfBlocks)
+	res, err := r.sc.GetAddressWithBiggestChange(c.Request.Context(), input.query())
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			c.AbortWithStatus(http.StatusGatewayTimeout)
@@ -57,6 +113,12 @@ func (r *statsOfChangingRoutes) getBiggestChange(c *gin.Context) {
 			return
 		}
 
+		if entity.RequestError(err) != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+
+			return
+		}
+
 		r.l.Error("http - v1 - getBiggestChange", sl.Err(err))
 		c.AbortWithStatus(http.StatusInternalServerError)
 
@@ -65,3 +127,188 @@ func (r *statsOfChangingRoutes) getBiggestChange(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+type getTopChangesRequest struct {
+	getBiggestChangeRequest
+	Limit uint `form:"limit"`
+}
+
+// @Summary     Получение рейтинга адресов, которые максимально изменились
+// @Description Получение limit адресов, которые максимально изменились за count_of_blocks блоков
+// @Description Адреса с одинаковым изменением упорядочены по адресу
+// @Description По умолчанию count_of_blocks = 100, limit = 10
+// @Description Границы from_block и to_block задаются так же, как в /get_biggest_change
+// @Tags  	    StatsOfChanging
+// @Param count_of_blocks query integer false "Количество последних блоков"
+// @Param from_block query integer false "Первый блок"
+// @Param to_block query integer false "Последний блок"
+// @Param token query string false "Адрес ERC-20 токена, изменения считаются в базовых единицах токена"
+// @Param anchor query string false "Блок, на котором заканчивается окно: latest, safe или finalized"
+// @Param since query string false "Начало окна по времени блоков в формате RFC 3339"
+// @Param duration query string false "Длительность окна по времени блоков, например 1h30m"
+// @Param metric query string false "Метрика ранжирования: net, inflow, outflow, gross, tx_count или relative, по умолчанию net"
+// @Param include query []string false "Адреса, среди которых ищутся изменения" collectionFormat(multi)
+// @Param exclude query []string false "Адреса, которые не учитываются при поиске" collectionFormat(multi)
+// @Param category query []string false "Категории меток адресов, среди которых ищутся изменения" collectionFormat(multi)
+// @Param exclude_category query []string false "Категории меток адресов, которые не учитываются" collectionFormat(multi)
+// @Param group_by query string false "Группировка: address или entity, entity суммирует адреса одной сущности (только для net)"
+// @Param balances query boolean false "Вернуть балансы адресов до и после окна и изменение в процентах"
+// @Param limit query integer false "Количество адресов в рейтинге, не больше maxTopLimit из конфига"
+// @Success     200 {object} entity.TopChanges "Рейтинг получен"
+// @Failure     400 "Ошибка в запросе"
+// @Failure     500 "Не удалось выполнить запрос"
+// @Failure     504 "Таймаут запроса"
+// @Router      /top_changes [get] .
+func (r *statsOfChangingRoutes) getTopChanges(c *gin.Context) {
+	var input getTopChangesRequest
+
+	if err := c.ShouldBind(&input); err != nil {
+		r.l.Error("http - v1 - getTopChanges", sl.Err(err))
+		c.AbortWithStatus(http.StatusBadRequest)
+
+		return
+	}
+
+	res, err := r.sc.GetTopChanges(c.Request.Context(), input.query(), input.Limit)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatus(http.StatusGatewayTimeout)
+
+			return
+		}
+
+		if entity.RequestError(err) != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+
+			return
+		}
+
+		r.l.Error("http - v1 - getTopChanges", sl.Err(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+type getBlockAtRequest struct {
+	Time time.Time `binding:"required" form:"time"`
+}
+
+// @Summary     Получение блока на момент времени
+// @Description Получение последнего блока, добытого не позже time
+// @Description Блок ищется бинарным поиском по заголовкам блоков
+// @Tags  	    StatsOfChanging
+// @Param time query string true "Время в формате RFC 3339"
+// @Success     200 {object} entity.BlockAtTime "Блок найден"
+// @Failure     400 "Ошибка в запросе"
+// @Failure     500 "Не удалось выполнить запрос"
+// @Failure     504 "Таймаут запроса"
+// @Router      /block_at [get] .
+func (r *statsOfChangingRoutes) getBlockAt(c *gin.Context) {
+	var input getBlockAtRequest
+
+	if err := c.ShouldBind(&input); err != nil {
+		r.l.Error("http - v1 - getBlockAt", sl.Err(err))
+		c.AbortWithStatus(http.StatusBadRequest)
+
+		return
+	}
+
+	res, err := r.sc.GetBlockAtTime(c.Request.Context(), input.Time)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatus(http.StatusGatewayTimeout)
+
+			return
+		}
+
+		if entity.RequestError(err) != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+
+			return
+		}
+
+		r.l.Error("http - v1 - getBlockAt", sl.Err(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// History of address has no ranking, so only window is described by request.
+type getAddressChangesRequest struct {
+	CountOfBlocks uint          `form:"count_of_blocks"`
+	FromBlock     *uint64       `form:"from_block"`
+	ToBlock       *uint64       `form:"to_block"`
+	Token         string        `form:"token"`
+	Anchor        string        `form:"anchor"`
+	Since         *time.Time    `form:"since"`
+	Duration      time.Duration `form:"duration"`
+}
+
+func (r *getAddressChangesRequest) query() entity.ChangesQuery {
+	return entity.ChangesQuery{
+		CountOfBlocks: r.CountOfBlocks,
+		FromBlock:     r.FromBlock,
+		ToBlock:       r.ToBlock,
+		Token:         r.Token,
+		Anchor:        r.Anchor,
+		Since:         r.Since,
+		Duration:      r.Duration,
+	}
+}
+
+// @Summary     Получение истории изменений адреса
+// @Description Получение изменений баланса address в каждом блоке окна и общего изменения
+// @Description Окно задаётся так же, как в /get_biggest_change
+// @Description Блоки, в которых баланс адреса не изменился, пропускаются
+// @Tags  	    StatsOfChanging
+// @Param address path string true "Адрес"
+// @Param count_of_blocks query integer false "Количество последних блоков"
+// @Param from_block query integer false "Первый блок"
+// @Param to_block query integer false "Последний блок"
+// @Param token query string false "Адрес ERC-20 токена, изменения считаются в базовых единицах токена"
+// @Param anchor query string false "Блок, на котором заканчивается окно: latest, safe или finalized"
+// @Param since query string false "Начало окна по времени блоков в формате RFC 3339"
+// @Param duration query string false "Длительность окна по времени блоков, например 1h30m"
+// @Success     200 {object} entity.AddressChanges "История получена"
+// @Failure     400 "Ошибка в запросе"
+// @Failure     500 "Не удалось выполнить запрос"
+// @Failure     504 "Таймаут запроса"
+// @Router      /addresses/{address}/changes [get] .
+func (r *statsOfChangingRoutes) getAddressChanges(c *gin.Context) {
+	var input getAddressChangesRequest
+
+	if err := c.ShouldBind(&input); err != nil {
+		r.l.Error("http - v1 - getAddressChanges", sl.Err(err))
+		c.AbortWithStatus(http.StatusBadRequest)
+
+		return
+	}
+
+	res, err := r.sc.GetAddressChanges(c.Request.Context(), c.Param("address"), input.query())
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.AbortWithStatus(http.StatusGatewayTimeout)
+
+			return
+		}
+
+		if entity.RequestError(err) != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+
+			return
+		}
+
+		r.l.Error("http - v1 - getAddressChanges", sl.Err(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/egor-denisov/biggest-change/internal/entity"
 	mock "github.com/egor-denisov/biggest-change/internal/usecase/mocks"
@@ -63,14 +64,22 @@ var testsGetBiggestChange = []struct {
 			res := &entity.BiggestChange{
 				Address:       "0x1",
 				Amount:        "0x100",
+				FirstBlock:    "0xf2",
 				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
 				CountOfBlocks: 50,
 				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
 			}
-			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), uint(50)).Return(res, nil)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 50}).Return(res, nil)
 		},
-		expectedStatusCode:   http.StatusOK,
-		expectedResponseBody: `{"address":"0x1","amount":"0x100","lastBlock":"0x123","countOfBlocks":50,"isRecieved":true}`,
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"address":"0x1","amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123"}`,
 	},
 	{
 		name:  "default count of blocks",
@@ -79,14 +88,224 @@ var testsGetBiggestChange = []struct {
 			res := &entity.BiggestChange{
 				Address:       "0x1",
 				Amount:        "0x100",
+				FirstBlock:    "0xf2",
 				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
 				CountOfBlocks: int64(100),
 				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
 			}
-			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), uint(0)).Return(res, nil)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{}).Return(res, nil)
 		},
-		expectedStatusCode:   http.StatusOK,
-		expectedResponseBody: `{"address":"0x1","amount":"0x100","lastBlock":"0x123","countOfBlocks":100,"isRecieved":true}`,
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"address":"0x1","amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":100,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123"}`,
+	},
+	{
+		name:  "block range",
+		query: `?from_block=242&to_block=291`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.BiggestChange{
+				Address:       "0x1",
+				Amount:        "0x100",
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			from, to := uint64(242), uint64(291)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{FromBlock: &from, ToBlock: &to}).
+				Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"address":"0x1","amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123"}`,
+	},
+	{
+		name:  "invalid block range",
+		query: `?from_block=300&to_block=291`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			from, to := uint64(300), uint64(291)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{FromBlock: &from, ToBlock: &to}).
+				Return(nil, entity.ErrInvalidBlockRange)
+		},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:  "finalized anchor",
+		query: `?count_of_blocks=50&anchor=finalized`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.BiggestChange{
+				Address:       "0x1",
+				Amount:        "0x100",
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "finalized",
+				AnchorBlock:   "0x123",
+			}
+			query := entity.ChangesQuery{CountOfBlocks: 50, Anchor: entity.AnchorFinalized}
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), query).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"address":"0x1","amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"finalized","anchorBlock":"0x123"}`,
+	},
+	{
+		name:  "invalid anchor",
+		query: `?anchor=pending`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{Anchor: "pending"}).
+				Return(nil, entity.ErrInvalidAnchor)
+		},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:  "time window",
+		query: `?since=2024-04-01T00:00:00Z&duration=1h`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.BiggestChange{
+				Address:        "0x1",
+				Amount:         "0x100",
+				FirstBlock:     "0xf2",
+				LastBlock:      "0x123",
+				FirstBlockTime: 1711929607,
+				LastBlockTime:  1711933199,
+				Metric:         entity.MetricNet,
+				CountOfBlocks:  50,
+				IsRecieved:     true,
+				BurnedFees:     "0x0",
+				Anchor:         "latest",
+				AnchorBlock:    "0x200",
+			}
+			since := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{Since: &since, Duration: time.Hour}).
+				Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"address":"0x1","amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":1711929607,"lastBlockTimestamp":1711933199,` +
+			`"metric":"net","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x200"}`,
+	},
+	{
+		name:  "explain",
+		query: `?count_of_blocks=50&explain=true`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.BiggestChange{
+				Address:       "0x1",
+				Amount:        "0x100",
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+				Transactions: []*entity.ContributingTransaction{
+					{Hash: "0xa", Block: "0x123", Value: "0x100", Fee: "0x0", IsRecieved: true},
+				},
+			}
+			query := entity.ChangesQuery{CountOfBlocks: 50, Explain: true}
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), query).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"address":"0x1","amount":"0x100","firstBlock":"0xf2","lastBlock":"0x123",` +
+			`"firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123",` +
+			`"transactions":[{"hash":"0xa","block":"0x123","value":"0x100","fee":"0x0","isRecieved":true}]}`,
+	},
+	{
+		name:  "relative metric",
+		query: `?count_of_blocks=50&metric=relative`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			percentChange := 600.0
+			res := &entity.BiggestChange{
+				Address:       "0x1",
+				Amount:        "0x12c",
+				StartBalance:  "0x32",
+				EndBalance:    "0x15e",
+				PercentChange: &percentChange,
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricRelative,
+				CountOfBlocks: 50,
+				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			query := entity.ChangesQuery{CountOfBlocks: 50, Metric: entity.MetricRelative}
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), query).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"address":"0x1","amount":"0x12c","startBalance":"0x32","endBalance":"0x15e",` +
+			`"percentChange":600,"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"relative","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123"}`,
+	},
+	{
+		name:  "balances",
+		query: `?count_of_blocks=50&balances=true`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.BiggestChange{
+				Address:       "0x1",
+				Amount:        "0x100",
+				StartBalance:  "0x0",
+				EndBalance:    "0x100",
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				IsRecieved:    true,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			query := entity.ChangesQuery{CountOfBlocks: 50, Balances: true}
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), query).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"address":"0x1","amount":"0x100","startBalance":"0x0","endBalance":"0x100",` +
+			`"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"isRecieved":true,"isNewContract":false,"failedTransactions":0,` +
+			`"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123"}`,
+	},
+	{
+		name:                 "invalid duration",
+		query:                `?duration=hour`,
+		mockBehavior:         func(_ *mock.MockStatsOfChanging) {},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:  "invalid token",
+		query: `?token=0x123`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{Token: "0x123"}).
+				Return(nil, entity.ErrInvalidAddress)
+		},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
 	},
 	{
 		name:                 "Bad request",
@@ -99,7 +318,7 @@ var testsGetBiggestChange = []struct {
 		name:  "Timeout",
 		query: ``,
 		mockBehavior: func(m *mock.MockStatsOfChanging) {
-			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), uint(0)).
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{}).
 				Return(nil, context.DeadlineExceeded)
 		},
 		expectedStatusCode:   http.StatusGatewayTimeout,
@@ -109,10 +328,466 @@ var testsGetBiggestChange = []struct {
 		name:  "Something went wrong",
 		query: ``,
 		mockBehavior: func(m *mock.MockStatsOfChanging) {
-			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), uint(0)).
+			m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{}).
 				Return(nil, errSomethingWentWrong)
 		},
 		expectedStatusCode:   http.StatusInternalServerError,
 		expectedResponseBody: ``,
 	},
 }
+
+func Test_getTopChanges(t *testing.T) {
+	for _, test := range testsGetTopChanges {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			usecase := mock.NewMockStatsOfChanging(c)
+			test.mockBehavior(usecase)
+
+			handler := statsOfChangingRoutes{
+				sc: usecase,
+				l:  logger.SetupLogger("debug"),
+			}
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/", handler.getTopChanges)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/"+test.query, nil)
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+const (
+	testAddressA = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	testAddressB = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+var testsGetTopChanges = []struct {
+	name                 string
+	mockBehavior         mockBehavior
+	query                string
+	expectedStatusCode   int
+	expectedResponseBody string
+}{
+	{
+		name:  "valid request",
+		query: `?count_of_blocks=50&limit=2`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.TopChanges{
+				Changes: []*entity.AddressChange{
+					{Address: "0x1", Amount: "0x100", IsRecieved: true},
+					{Address: "0x2", Amount: "0x10", IsRecieved: false},
+				},
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 50}, uint(2)).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"changes":[{"address":"0x1","amount":"0x100","isRecieved":true,"isNewContract":false},` +
+			`{"address":"0x2","amount":"0x10","isRecieved":false,"isNewContract":false}],"firstBlock":"0xf2",` +
+			`"lastBlock":"0x123","firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"failedTransactions":0,"burnedFees":"0x0","anchor":"latest",` +
+			`"anchorBlock":"0x123"}`,
+	},
+	{
+		name:  "default params",
+		query: ``,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.TopChanges{
+				Changes:       []*entity.AddressChange{},
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 100,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{}, uint(0)).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"changes":[],"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,` +
+			`"lastBlockTimestamp":0,"metric":"net","countOfBlocks":100,` +
+			`"failedTransactions":0,"burnedFees":"0x0","anchor":"latest","anchorBlock":"0x123"}`,
+	},
+	{
+		name:  "token",
+		query: `?token=0xdac17f958d2ee523a2206206994597c13d831ec7&limit=1`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.TopChanges{
+				Changes:       []*entity.AddressChange{{Address: "0x1", Amount: "0x100", IsRecieved: true}},
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+				Token:         &entity.Token{Address: "0xdac17f958d2ee523a2206206994597c13d831ec7", Decimals: 6},
+			}
+			query := entity.ChangesQuery{Token: "0xdac17f958d2ee523a2206206994597c13d831ec7"}
+			m.EXPECT().GetTopChanges(gomock.Any(), query, uint(1)).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"changes":[{"address":"0x1","amount":"0x100","isRecieved":true,"isNewContract":false}],` +
+			`"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"failedTransactions":0,"burnedFees":"0x0",` +
+			`"anchor":"latest","anchorBlock":"0x123",` +
+			`"token":{"address":"0xdac17f958d2ee523a2206206994597c13d831ec7","decimals":6}}`,
+	},
+	{
+		name:  "gross metric",
+		query: `?count_of_blocks=50&limit=1&metric=gross`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.TopChanges{
+				Changes:       []*entity.AddressChange{{Address: "0x1", Amount: "0x300", IsRecieved: false}},
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricGross,
+				CountOfBlocks: 50,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			query := entity.ChangesQuery{CountOfBlocks: 50, Metric: entity.MetricGross}
+			m.EXPECT().GetTopChanges(gomock.Any(), query, uint(1)).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"changes":[{"address":"0x1","amount":"0x300","isRecieved":false,"isNewContract":false}],` +
+			`"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"gross","countOfBlocks":50,"failedTransactions":0,"burnedFees":"0x0",` +
+			`"anchor":"latest","anchorBlock":"0x123"}`,
+	},
+	{
+		name:  "filters",
+		query: "?limit=1&include=" + testAddressA + "&include=" + testAddressB + "&exclude=" + testAddressB,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.TopChanges{
+				Changes:       []*entity.AddressChange{{Address: testAddressA, Amount: "0x100"}},
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+				Filters: &entity.AddressFilters{
+					Include: []string{testAddressA, testAddressB},
+					Exclude: []string{testAddressB},
+				},
+			}
+			query := entity.ChangesQuery{
+				Include: []string{testAddressA, testAddressB},
+				Exclude: []string{testAddressB},
+			}
+			m.EXPECT().GetTopChanges(gomock.Any(), query, uint(1)).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"changes":[{"address":"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa","amount":"0x100",` +
+			`"isRecieved":false,"isNewContract":false}],` +
+			`"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"failedTransactions":0,"burnedFees":"0x0",` +
+			`"anchor":"latest","anchorBlock":"0x123","filters":{` +
+			`"include":["0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa","0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"],` +
+			`"exclude":["0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"]}}`,
+	},
+	{
+		name:  "category",
+		query: `?limit=1&category=exchange`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.TopChanges{
+				Changes: []*entity.AddressChange{{
+					Address: testAddressA,
+					Label:   &entity.Label{Name: "Binance 14", Category: "exchange"},
+					Amount:  "0x100",
+				}},
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				CountOfBlocks: 50,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+				Filters:       &entity.AddressFilters{Categories: []string{"exchange"}},
+			}
+			query := entity.ChangesQuery{Categories: []string{"exchange"}}
+			m.EXPECT().GetTopChanges(gomock.Any(), query, uint(1)).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"changes":[{"address":"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",` +
+			`"label":{"name":"Binance 14","category":"exchange"},"amount":"0x100",` +
+			`"isRecieved":false,"isNewContract":false}],` +
+			`"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","countOfBlocks":50,"failedTransactions":0,"burnedFees":"0x0",` +
+			`"anchor":"latest","anchorBlock":"0x123","filters":{"categories":["exchange"]}}`,
+	},
+	{
+		name:  "group by entity",
+		query: `?limit=1&group_by=entity`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.TopChanges{
+				Changes: []*entity.AddressChange{{
+					Address:   "binance",
+					Entity:    "binance",
+					Addresses: []string{testAddressA, testAddressB},
+					Amount:    "0x100",
+				}},
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				Metric:        entity.MetricNet,
+				GroupBy:       entity.GroupByEntity,
+				CountOfBlocks: 50,
+				BurnedFees:    "0x0",
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			query := entity.ChangesQuery{GroupBy: entity.GroupByEntity}
+			m.EXPECT().GetTopChanges(gomock.Any(), query, uint(1)).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"changes":[{"address":"binance","entity":"binance","addresses":[` +
+			`"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa","0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"],` +
+			`"amount":"0x100","isRecieved":false,"isNewContract":false}],` +
+			`"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,"lastBlockTimestamp":0,` +
+			`"metric":"net","groupBy":"entity","countOfBlocks":50,"failedTransactions":0,"burnedFees":"0x0",` +
+			`"anchor":"latest","anchorBlock":"0x123"}`,
+	},
+	{
+		name:  "invalid grouping",
+		query: `?group_by=owner`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{GroupBy: "owner"}, uint(0)).
+				Return(nil, entity.ErrInvalidGroupBy)
+		},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:  "invalid metric",
+		query: `?metric=volume`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{Metric: "volume"}, uint(0)).
+				Return(nil, entity.ErrInvalidMetric)
+		},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:                 "Bad request",
+		query:                `?limit=-1`,
+		mockBehavior:         func(_ *mock.MockStatsOfChanging) {},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:  "Timeout",
+		query: ``,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{}, uint(0)).
+				Return(nil, context.DeadlineExceeded)
+		},
+		expectedStatusCode:   http.StatusGatewayTimeout,
+		expectedResponseBody: ``,
+	},
+	{
+		name:  "Something went wrong",
+		query: ``,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{}, uint(0)).
+				Return(nil, errSomethingWentWrong)
+		},
+		expectedStatusCode:   http.StatusInternalServerError,
+		expectedResponseBody: ``,
+	},
+}
+
+func Test_getBlockAt(t *testing.T) {
+	for _, test := range testsGetBlockAt {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			usecase := mock.NewMockStatsOfChanging(c)
+			test.mockBehavior(usecase)
+
+			handler := statsOfChangingRoutes{
+				sc: usecase,
+				l:  logger.SetupLogger("debug"),
+			}
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/", handler.getBlockAt)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/"+test.query, nil)
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+var testsGetBlockAt = []struct {
+	name                 string
+	mockBehavior         mockBehavior
+	query                string
+	expectedStatusCode   int
+	expectedResponseBody string
+}{
+	{
+		name:  "valid request",
+		query: `?time=2024-04-01T00:00:00Z`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.BlockAtTime{Number: "0x12bba85", Hash: "0xh", Timestamp: 1711929599}
+			m.EXPECT().GetBlockAtTime(gomock.Any(), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)).Return(res, nil)
+		},
+		expectedStatusCode:   http.StatusOK,
+		expectedResponseBody: `{"number":"0x12bba85","hash":"0xh","timestamp":1711929599}`,
+	},
+	{
+		name:                 "missing time",
+		query:                ``,
+		mockBehavior:         func(_ *mock.MockStatsOfChanging) {},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:  "time before genesis",
+		query: `?time=2000-01-01T00:00:00Z`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetBlockAtTime(gomock.Any(), time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).
+				Return(nil, entity.ErrTimeBeforeGenesis)
+		},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:  "Something went wrong",
+		query: `?time=2024-04-01T00:00:00Z`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetBlockAtTime(gomock.Any(), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)).
+				Return(nil, errSomethingWentWrong)
+		},
+		expectedStatusCode:   http.StatusInternalServerError,
+		expectedResponseBody: ``,
+	},
+}
+
+func Test_getAddressChanges(t *testing.T) {
+	for _, test := range testsGetAddressChanges {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			usecase := mock.NewMockStatsOfChanging(c)
+			test.mockBehavior(usecase)
+
+			handler := statsOfChangingRoutes{
+				sc: usecase,
+				l:  logger.SetupLogger("debug"),
+			}
+			// Init Endpoint
+			r := gin.New()
+			r.GET("/addresses/:address/changes", handler.getAddressChanges)
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/addresses/"+test.query, nil)
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+var testsGetAddressChanges = []struct {
+	name                 string
+	mockBehavior         mockBehavior
+	query                string
+	expectedStatusCode   int
+	expectedResponseBody string
+}{
+	{
+		name:  "valid request",
+		query: `0x1/changes?count_of_blocks=50`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			res := &entity.AddressChanges{
+				Address:       "0x1",
+				Changes:       []*entity.BlockChange{{Block: "0xf3", Amount: "0x100", IsRecieved: true}},
+				Total:         "0x100",
+				IsRecieved:    true,
+				FirstBlock:    "0xf2",
+				LastBlock:     "0x123",
+				CountOfBlocks: 50,
+				Anchor:        "latest",
+				AnchorBlock:   "0x123",
+			}
+			m.EXPECT().GetAddressChanges(gomock.Any(), "0x1", entity.ChangesQuery{CountOfBlocks: 50}).Return(res, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"address":"0x1","changes":[{"block":"0xf3","amount":"0x100","isRecieved":true}],` +
+			`"total":"0x100","isRecieved":true,"firstBlock":"0xf2","lastBlock":"0x123","firstBlockTimestamp":0,` +
+			`"lastBlockTimestamp":0,"countOfBlocks":50,"anchor":"latest","anchorBlock":"0x123"}`,
+	},
+	{
+		name:  "ranking parameters are not used",
+		query: `0x1/changes?count_of_blocks=50&metric=gross&explain=true&group_by=entity`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetAddressChanges(gomock.Any(), "0x1", entity.ChangesQuery{CountOfBlocks: 50}).
+				Return(&entity.AddressChanges{Address: "0x1"}, nil)
+		},
+		expectedStatusCode: http.StatusOK,
+		expectedResponseBody: `{"address":"0x1","changes":null,"total":"","isRecieved":false,"firstBlock":"",` +
+			`"lastBlock":"","firstBlockTimestamp":0,"lastBlockTimestamp":0,"countOfBlocks":0,"anchor":"",` +
+			`"anchorBlock":""}`,
+	},
+	{
+		name:  "invalid address",
+		query: `0x1/changes`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetAddressChanges(gomock.Any(), "0x1", entity.ChangesQuery{}).
+				Return(nil, entity.ErrInvalidAddress)
+		},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:                 "Bad request",
+		query:                `0x1/changes?count_of_blocks=hello`,
+		mockBehavior:         func(_ *mock.MockStatsOfChanging) {},
+		expectedStatusCode:   http.StatusBadRequest,
+		expectedResponseBody: ``,
+	},
+	{
+		name:  "Timeout",
+		query: `0x1/changes`,
+		mockBehavior: func(m *mock.MockStatsOfChanging) {
+			m.EXPECT().GetAddressChanges(gomock.Any(), "0x1", entity.ChangesQuery{}).
+				Return(nil, context.DeadlineExceeded)
+		},
+		expectedStatusCode:   http.StatusGatewayTimeout,
+		expectedResponseBody: ``,
+	},
+}
@@ -0,0 +1,33 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/egor-denisov/biggest-change/internal/controller/sse"
+	"github.com/gin-gonic/gin"
+)
+
+type streamRoutes struct {
+	h http.Handler
+}
+
+func newStream(handler *gin.RouterGroup, stream *sse.Stream) {
+	r := &streamRoutes{stream.Handler()}
+
+	handler.GET("/stream", r.stream)
+}
+
+// @Summary     Поток событий новых блоков (SSE)
+// @Description На каждый обработанный блок отправляется событие block с id, равным номеру блока
+// @Description Данные события: {"block": "0x...", "change": {...}, "top": {...}} для окна по умолчанию
+// @Description При переподключении с заголовком Last-Event-ID присылаются пропущенные события из истории
+// @Tags  	    StatsOfChanging
+// @Produce     text/event-stream
+// @Param Last-Event-ID header integer false "Номер блока последнего полученного события"
+// @Param last_event_id query integer false "То же, что Last-Event-ID, для клиентов без заголовков"
+// @Success     200 "Поток событий"
+// @Failure     400 "Неверный Last-Event-ID"
+// @Router      /stream [get] .
+func (r *streamRoutes) stream(c *gin.Context) {
+	r.h.ServeHTTP(c.Writer, c.Request)
+}
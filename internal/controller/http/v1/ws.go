@@ -0,0 +1,32 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/egor-denisov/biggest-change/internal/controller/ws"
+	"github.com/gin-gonic/gin"
+)
+
+type wsRoutes struct {
+	h http.Handler
+}
+
+func newWS(handler *gin.RouterGroup, hub *ws.Hub) {
+	r := &wsRoutes{hub.Handler()}
+
+	handler.GET("/ws", r.subscribe)
+}
+
+// @Summary     Подписка на изменения через websocket
+// @Description Соединение переключается на websocket, сообщения передаются в формате JSON
+// @Description Подписка: {"type": "subscribe", "id": "a", "query": {"countOfBlocks": 100, "metric": "net", "limit": 10}}
+// @Description Если limit не задан, присылается наибольшее изменение, иначе рейтинг из limit адресов
+// @Description Текущий результат присылается сразу, затем после каждого блока, который его изменил
+// @Description Отписка: {"type": "unsubscribe", "id": "a"}
+// @Tags  	    StatsOfChanging
+// @Success     101 "Соединение переключено на websocket"
+// @Failure     503 "Превышено количество соединений"
+// @Router      /ws [get] .
+func (r *wsRoutes) subscribe(c *gin.Context) {
+	r.h.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,106 @@
+package sse
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidLastEventID = errors.New("invalid last event id")
+
+// Getting handler which sends events until client disconnects.
+// Missed events are sent first if client resumes stream with Last-Event-ID.
+func (s *Stream) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastID, err := lastEventID(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		rc := http.NewResponseController(w)
+		// Stream is longer than write timeout of server
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		// Proxies mustn't buffer events
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		ch, missed := s.subscribe(lastID)
+		defer s.unsubscribe(ch)
+
+		for _, e := range missed {
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		ticker := time.NewTicker(s.keepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e, ok := <-ch:
+				// Channel is closed if subscriber is too slow or stream is stopped
+				if !ok {
+					return
+				}
+
+				err = writeEvent(w, e)
+			case <-ticker.C:
+				// Comment keeps connection open through proxies
+				_, err = io.WriteString(w, ": keep-alive\n\n")
+			}
+
+			if err == nil {
+				err = rc.Flush()
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	})
+}
+
+// Getting id of last received event from header or from query for clients which can't set headers.
+func lastEventID(r *http.Request) (*uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+
+	if value == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLastEventID, value)
+	}
+
+	return &id, nil
+}
+
+func writeEvent(w io.Writer, e *Event) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: block\ndata: %s\n\n", e.ID, e.Data)
+
+	return err
+}
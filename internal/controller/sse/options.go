@@ -0,0 +1,17 @@
+package sse
+
+import "time"
+
+type Option func(*Stream)
+
+func History(size int) Option {
+	return func(s *Stream) {
+		s.historySize = size
+	}
+}
+
+func KeepAlive(keepAlive time.Duration) Option {
+	return func(s *Stream) {
+		s.keepAlive = keepAlive
+	}
+}
@@ -0,0 +1,209 @@
+// Package sse implements stream of results of default window as server-sent events.
+// Event is computed once per processed block, the latest events are kept for resumption.
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/egor-denisov/biggest-change/internal/usecase"
+	sl "github.com/egor-denisov/biggest-change/pkg/logger"
+)
+
+const (
+	_defaultHistory   = 100
+	_defaultKeepAlive = 15 * time.Second
+	// Subscriber which is behind by more events is dropped, it can resume from history.
+	_subscriberBuffer = 16
+)
+
+// Event of processed block, its id is number of block.
+type Event struct {
+	ID   uint64
+	Data []byte
+}
+
+// Data of event.
+type blockEvent struct {
+	Block  string                `json:"block"`
+	Change *entity.BiggestChange `json:"change"`
+	Top    *entity.TopChanges    `json:"top"`
+}
+
+type Stream struct {
+	sc          usecase.StatsOfChanging
+	l           *slog.Logger
+	historySize int
+	keepAlive   time.Duration
+	mu          sync.Mutex
+	history     []*Event // Ordered by processing
+	subscribers map[chan *Event]struct{}
+	notify      chan struct{}
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+func New(l *slog.Logger, sc usecase.StatsOfChanging, opts ...Option) *Stream {
+	s := &Stream{
+		sc:          sc,
+		l:           l,
+		historySize: _defaultHistory,
+		keepAlive:   _defaultKeepAlive,
+		subscribers: make(map[chan *Event]struct{}),
+		notify:      make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Starting computing of events.
+func (s *Stream) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+}
+
+// Stopping computing of events and closing all subscriptions.
+func (s *Stream) Stop() {
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subscribers {
+		delete(s.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Notifying stream about new block, it's listener of follower.
+// Blocks which come during computing are joined, so slow computing doesn't block follower.
+func (s *Stream) Notify(_ *big.Int) {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Stream) run(ctx context.Context) {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.notify:
+			e, err := s.compute(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.l.Error("sse - Stream - compute", sl.Err(err))
+				}
+
+				continue
+			}
+
+			s.publish(e)
+		}
+	}
+}
+
+// Computing the biggest change and top of default window.
+func (s *Stream) compute(ctx context.Context) (*Event, error) {
+	change, err := s.sc.GetAddressWithBiggestChange(ctx, entity.ChangesQuery{})
+	if err != nil {
+		return nil, fmt.Errorf("Stream - compute - s.sc.GetAddressWithBiggestChange: %w", err)
+	}
+
+	top, err := s.sc.GetTopChanges(ctx, entity.ChangesQuery{}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Stream - compute - s.sc.GetTopChanges: %w", err)
+	}
+
+	id, err := strconv.ParseUint(strings.TrimPrefix(change.LastBlock, "0x"), 16, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Stream - compute - strconv.ParseUint: %w", err)
+	}
+
+	data, err := json.Marshal(&blockEvent{Block: change.LastBlock, Change: change, Top: top})
+	if err != nil {
+		return nil, fmt.Errorf("Stream - compute - json.Marshal: %w", err)
+	}
+
+	return &Event{ID: id, Data: data}, nil
+}
+
+// Adding event to history and sending it to subscribers.
+// Subscriber which doesn't read events is dropped instead of blocking others.
+func (s *Stream) publish(e *Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = append(s.history, e)
+	if len(s.history) > s.historySize {
+		s.history = s.history[len(s.history)-s.historySize:]
+	}
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- e:
+		default:
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribing on events, events from history with block after lastID are returned too.
+// New subscriber without lastID gets the latest event, so it has current result at once.
+// Subscription and history are taken under one lock, so no event is lost between them.
+func (s *Stream) subscribe(lastID *uint64) (chan *Event, []*Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var missed []*Event
+
+	switch {
+	case lastID != nil:
+		for _, e := range s.history {
+			if e.ID > *lastID {
+				missed = append(missed, e)
+			}
+		}
+	case len(s.history) > 0:
+		missed = s.history[len(s.history)-1:]
+	}
+
+	ch := make(chan *Event, _subscriberBuffer)
+	s.subscribers[ch] = struct{}{}
+
+	return ch, missed
+}
+
+func (s *Stream) unsubscribe(ch chan *Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[ch]; ok {
+		delete(s.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,161 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	mock "github.com/egor-denisov/biggest-change/internal/usecase/mocks"
+	"github.com/egor-denisov/biggest-change/pkg/logger"
+	"github.com/go-playground/assert"
+	"github.com/golang/mock/gomock"
+)
+
+// Processing block of fake use case as if follower notified stream about it.
+func process(t *testing.T, s *Stream, m *mock.MockStatsOfChanging, block uint64) {
+	t.Helper()
+
+	hex := fmt.Sprintf("0x%x", block)
+	m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{}).
+		Return(&entity.BiggestChange{Address: "0x1", Amount: "0x10", LastBlock: hex}, nil)
+	m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{}, uint(0)).
+		Return(&entity.TopChanges{Changes: []*entity.AddressChange{{Address: "0x1", Amount: "0x10"}}, LastBlock: hex}, nil)
+
+	e, err := s.compute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.publish(e)
+}
+
+// Opening stream, header Last-Event-ID is set if it isn't empty.
+func open(t *testing.T, url, lastEventID string) *bufio.Reader {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = res.Body.Close() })
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	return bufio.NewReader(res.Body)
+}
+
+// Reading event from stream, comments are skipped.
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+
+	var lines []string
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && len(lines) > 0:
+			return strings.Join(lines, "\n")
+		case line == "" || strings.HasPrefix(line, ":"):
+			continue
+		default:
+			lines = append(lines, line)
+		}
+	}
+}
+
+func event(block uint64) string {
+	return fmt.Sprintf("id: %d\nevent: block\n"+
+		`data: {"block":"0x%x","change":{"address":"0x1","amount":"0x10","firstBlock":"","lastBlock":"0x%x",`+
+		`"firstBlockTimestamp":0,"lastBlockTimestamp":0,"metric":"","countOfBlocks":0,"isRecieved":false,`+
+		`"isNewContract":false,"failedTransactions":0,"burnedFees":"","anchor":"","anchorBlock":""},`+
+		`"top":{"changes":[{"address":"0x1","amount":"0x10","isRecieved":false,"isNewContract":false}],`+
+		`"firstBlock":"","lastBlock":"0x%x","firstBlockTimestamp":0,"lastBlockTimestamp":0,"metric":"",`+
+		`"countOfBlocks":0,"failedTransactions":0,"burnedFees":"","anchor":"","anchorBlock":""}}`,
+		block, block, block, block)
+}
+
+func Test_Stream(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	m := mock.NewMockStatsOfChanging(c)
+	s := New(logger.SetupLogger("debug"), m, History(2), KeepAlive(10*time.Millisecond))
+
+	// Server is closed after bodies of streams
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+
+	for block := uint64(200); block <= 202; block++ {
+		process(t, s, m, block)
+	}
+
+	// Only the latest event is sent to new client
+	latest := open(t, server.URL, "")
+	assert.Equal(t, event(202), readEvent(t, latest))
+
+	// Event of block 200 is out of history, so resumed stream starts from block 201
+	resumed := open(t, server.URL, "199")
+	assert.Equal(t, event(201), readEvent(t, resumed))
+	assert.Equal(t, event(202), readEvent(t, resumed))
+
+	process(t, s, m, 203)
+
+	assert.Equal(t, event(203), readEvent(t, latest))
+	assert.Equal(t, event(203), readEvent(t, resumed))
+
+	// Keep-alive comment is sent while there are no events
+	line, err := latest.ReadString('\n')
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ": keep-alive\n", line)
+}
+
+func Test_Stream_InvalidLastEventID(t *testing.T) {
+	s := New(logger.SetupLogger("debug"), nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/stream?last_event_id=0xc8", http.NoBody)
+	s.Handler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func Test_Stream_SlowSubscriber(t *testing.T) {
+	s := New(logger.SetupLogger("debug"), nil)
+	ch, _ := s.subscribe(nil)
+
+	for i := 0; i <= _subscriberBuffer; i++ {
+		s.publish(&Event{ID: uint64(i)})
+	}
+
+	// Subscriber gets buffered events and then its channel is closed
+	for i := 0; i < _subscriberBuffer; i++ {
+		e := <-ch
+		assert.Equal(t, uint64(i), e.ID)
+	}
+
+	_, ok := <-ch
+	assert.Equal(t, false, ok)
+}
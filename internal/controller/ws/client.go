@@ -0,0 +1,220 @@
+package ws
+
+import (
+	"context"
+	"sync"
+)
+
+// Limit of messages which aren't sent yet.
+// Updates of subscription replace each other, so only client which doesn't read replies reaches it.
+const _maxPending = 64
+
+type subscription struct {
+	query query
+	last  string // Signature of last sent update
+}
+
+// Client is subscriber of hub, messages to it are queued until connection sends them.
+type Client struct {
+	hub     *Hub
+	mu      sync.Mutex
+	subs    map[string]*subscription
+	pending []*Message
+	closed  bool
+	ready   chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newClient(h *Hub) *Client {
+	return &Client{
+		hub:   h,
+		subs:  make(map[string]*subscription),
+		ready: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+}
+
+// Subscribing on query, current result is computed by hub and sent as soon as it's ready.
+func (c *Client) subscribe(ctx context.Context, id string, q query) error {
+	if err := c.check(id); err != nil {
+		return err
+	}
+
+	if err := c.hub.acquire(q); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	// Subscriptions of closed client are already released
+	if c.closed {
+		c.mu.Unlock()
+		c.hub.release(q)
+
+		return ErrClientClosed
+	}
+
+	sub := &subscription{query: q}
+	c.subs[id] = sub
+	c.mu.Unlock()
+
+	go c.hub.computeFirst(ctx, c, id, sub)
+
+	return nil
+}
+
+// Sending the first result of subscription.
+// It's skipped if subscription is removed or it has already got newer update from hub.
+func (c *Client) first(id string, sub *subscription, msg *Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.subs[id] != sub || sub.last != "" {
+		return
+	}
+
+	sub.last = msg.signature()
+	c.push(msg.to(id))
+}
+
+// Removing subscription which can't be computed, false is returned if it's already removed.
+func (c *Client) drop(id string, sub *subscription) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.subs[id] != sub {
+		return false
+	}
+
+	delete(c.subs, id)
+
+	return true
+}
+
+// Checking that subscription can be added.
+func (c *Client) check(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.subs[id]; ok {
+		return ErrSubscriptionExists
+	}
+
+	if len(c.subs) >= c.hub.maxSubscriptions {
+		return ErrTooManySubscriptions
+	}
+
+	return nil
+}
+
+// Removing subscription with its update which isn't sent yet.
+func (c *Client) unsubscribe(id string) error {
+	c.mu.Lock()
+
+	sub, ok := c.subs[id]
+	if !ok {
+		c.mu.Unlock()
+
+		return ErrSubscriptionNotFound
+	}
+
+	delete(c.subs, id)
+
+	for i, msg := range c.pending {
+		if msg.Type == TypeUpdate && msg.ID == id {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+
+			break
+		}
+	}
+	// Mutex of hub is locked before mutex of client, so query is released after unlock
+	c.mu.Unlock()
+	c.hub.release(sub.query)
+
+	return nil
+}
+
+// Queueing reply to message of client.
+func (c *Client) reply(msg *Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.push(msg)
+}
+
+// Queueing update for subscriptions on query, if result isn't changed since last update it's skipped.
+func (c *Client) deliver(q query, msg *Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	signature := msg.signature()
+
+	for id, sub := range c.subs {
+		if sub.query != q || sub.last == signature {
+			continue
+		}
+
+		sub.last = signature
+		c.push(msg.to(id))
+	}
+}
+
+// Getting queued messages, queue is emptied.
+func (c *Client) take() []*Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := c.pending
+	c.pending = nil
+
+	return res
+}
+
+// Closing client, it's removed from hub with its subscriptions.
+func (c *Client) Close() {
+	c.disconnect()
+	c.hub.removeClient(c)
+
+	c.mu.Lock()
+	subs := c.subs
+	c.subs = make(map[string]*subscription)
+	c.closed = true
+	c.mu.Unlock()
+
+	for _, sub := range subs {
+		c.hub.release(sub.query)
+	}
+}
+
+// Adding message to queue, mutex of client must be locked.
+// Update which isn't sent yet is replaced, so slow client gets only the latest result.
+// Client is disconnected if it doesn't read messages.
+func (c *Client) push(msg *Message) {
+	if msg.Type == TypeUpdate {
+		for i, p := range c.pending {
+			if p.Type == TypeUpdate && p.ID == msg.ID {
+				c.pending[i] = msg
+
+				return
+			}
+		}
+	}
+
+	if len(c.pending) >= _maxPending {
+		c.disconnect()
+
+		return
+	}
+
+	c.pending = append(c.pending, msg)
+
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Signaling connection to stop, it can be called under locks of hub.
+func (c *Client) disconnect() {
+	c.once.Do(func() { close(c.done) })
+}
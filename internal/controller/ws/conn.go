@@ -0,0 +1,206 @@
+package ws
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	sl "github.com/egor-denisov/biggest-change/pkg/logger"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	_writeWait      = 10 * time.Second
+	_maxMessageSize = 4096
+)
+
+// Getting handler which upgrades connection to websocket and subscribes it on hub.
+// Clients over limit are rejected before upgrade.
+func (h *Hub) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := h.newClient()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+			return
+		}
+		defer c.Close()
+
+		s := websocket.Server{
+			Handler: func(conn *websocket.Conn) { h.serve(conn, c) },
+			// Api is public, so origin isn't checked
+			Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		}
+
+		s.ServeHTTP(&keepAliveWriter{ResponseWriter: w, wait: h.pongWait}, r)
+	})
+}
+
+// Sending messages of client until connection or client is closed.
+// Messages from client are read in another goroutine.
+func (h *Hub) serve(conn *websocket.Conn, c *Client) {
+	conn.MaxPayloadBytes = _maxMessageSize
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		defer cancel()
+
+		h.read(ctx, conn, c)
+	}()
+
+	if err := h.write(ctx, conn, c); err != nil {
+		h.l.Debug("ws - Hub - write", sl.Err(err))
+	}
+	// Reading is stopped by closing of connection
+	_ = conn.Close()
+}
+
+// Reading subscriptions of client.
+// Invalid message is answered by error, connection is closed only if it can't be read.
+// Results of subscriptions are computed by hub, so reading isn't stopped by slow upstream.
+func (h *Hub) read(ctx context.Context, conn *websocket.Conn, c *Client) {
+	for {
+		var data []byte
+
+		if err := websocket.Message.Receive(conn, &data); err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				h.l.Debug("ws - Hub - read", sl.Err(err))
+			}
+
+			return
+		}
+
+		var req request
+
+		err := json.Unmarshal(data, &req)
+		if err != nil {
+			err = fmt.Errorf("%w: %s", ErrInvalidMessage, err)
+		} else {
+			err = h.handle(ctx, c, &req)
+		}
+
+		if err != nil {
+			c.reply(&Message{Type: TypeError, ID: req.ID, Error: h.errorText(err)})
+		}
+	}
+}
+
+func (h *Hub) handle(ctx context.Context, c *Client, req *request) error {
+	switch req.Type {
+	case TypeSubscribe:
+		return c.subscribe(ctx, req.ID, req.Query)
+	case TypeUnsubscribe:
+		return c.unsubscribe(req.ID)
+	default:
+		return ErrUnknownType
+	}
+}
+
+// Getting text of error for client, internal errors are only logged.
+func (h *Hub) errorText(err error) string {
+	if e := entity.RequestError(err); e != nil {
+		return e.Error()
+	}
+
+	for _, e := range _clientErrors {
+		if errors.Is(err, e) {
+			return err.Error()
+		}
+	}
+
+	h.l.Error("ws - Hub - handle", sl.Err(err))
+
+	return entity.ErrInternalServer.Error()
+}
+
+// Writing queued messages and pings.
+// Connection is closed if client doesn't read messages during write wait.
+func (h *Hub) write(ctx context.Context, conn *websocket.Conn, c *Client) error {
+	// Client has half of wait to answer ping
+	ticker := time.NewTicker(h.pongWait / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.done:
+			return ErrClientClosed
+		case <-ticker.C:
+			if err := ping(conn); err != nil {
+				return err
+			}
+		case <-c.ready:
+			for _, msg := range c.take() {
+				_ = conn.SetWriteDeadline(time.Now().Add(_writeWait))
+
+				if err := websocket.JSON.Send(conn, msg); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// Codec of empty ping frame, payload type is set per frame, so connection isn't changed.
+var pingCodec = websocket.Codec{
+	Marshal: func(any) ([]byte, byte, error) { return nil, websocket.PingFrame, nil },
+}
+
+// Sending ping frame, pong is answered by client automatically.
+// It's called only by goroutine which writes messages.
+func ping(conn *websocket.Conn) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(_writeWait))
+
+	return pingCodec.Send(conn, nil)
+}
+
+// Response writer which prolongs read deadline of hijacked connection on each read.
+// Control frames are handled inside of websocket package, so pong can be noticed only by reading of bytes.
+type keepAliveWriter struct {
+	http.ResponseWriter
+	wait time.Duration
+}
+
+func (w *keepAliveWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	// Deadline of server is replaced, connection is open while client answers pings
+	_ = conn.SetDeadline(time.Time{})
+	_ = conn.SetReadDeadline(time.Now().Add(w.wait))
+
+	r := bufio.NewReader(&deadlineReader{r: rw.Reader, conn: conn, wait: w.wait})
+
+	return conn, bufio.NewReadWriter(r, rw.Writer), nil
+}
+
+type deadlineReader struct {
+	r    io.Reader
+	conn net.Conn
+	wait time.Duration
+}
+
+func (r *deadlineReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		_ = r.conn.SetReadDeadline(time.Now().Add(r.wait))
+	}
+
+	return n, err
+}
@@ -0,0 +1,318 @@
+// Package ws implements websocket subscriptions on changes of window.
+// Results are computed once per block for each distinct subscription and sent to all its clients.
+package ws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/egor-denisov/biggest-change/internal/usecase"
+	sl "github.com/egor-denisov/biggest-change/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	_defaultMaxClients       = 1000
+	_defaultMaxSubscriptions = 10
+	_defaultMaxQueries       = 100
+	_defaultMaxCountOfBlocks = 1000
+	_defaultWorkers          = 4
+	_defaultComputeTimeout   = 10 * time.Second
+	_defaultPongWait         = 60 * time.Second
+)
+
+var (
+	ErrTooManyClients       = errors.New("too many clients")
+	ErrTooManySubscriptions = errors.New("too many subscriptions")
+	ErrTooManyQueries       = errors.New("too many distinct subscriptions")
+	ErrWindowTooLarge       = errors.New("window of subscription is too large")
+	ErrSubscriptionExists   = errors.New("subscription already exists")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrUnknownType          = errors.New("unknown type of message")
+	ErrInvalidMessage       = errors.New("invalid message")
+	ErrClientClosed         = errors.New("client is closed")
+
+	// Errors which are caused by client, they are sent to it.
+	_clientErrors = []error{
+		ErrTooManySubscriptions,
+		ErrTooManyQueries,
+		ErrWindowTooLarge,
+		ErrSubscriptionExists,
+		ErrSubscriptionNotFound,
+		ErrUnknownType,
+		ErrInvalidMessage,
+	}
+
+	connectedClients = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "biggest_change_ws_clients",
+		Help: "Count of connected websocket clients.",
+	})
+)
+
+// Hub computes each distinct query once per block, so count of distinct queries and their windows are limited.
+type Hub struct {
+	sc               usecase.StatsOfChanging
+	l                *slog.Logger
+	maxClients       int
+	maxSubscriptions int
+	maxQueries       int
+	maxCountOfBlocks uint
+	workers          int
+	computeTimeout   time.Duration
+	pongWait         time.Duration
+	mu               sync.Mutex
+	clients          map[*Client]struct{}
+	queries          map[query]int // Count of subscriptions on each distinct query
+	pool             chan struct{} // Workers which compute results of queries
+	notify           chan struct{}
+	cancel           context.CancelFunc
+	done             chan struct{}
+}
+
+func New(l *slog.Logger, sc usecase.StatsOfChanging, opts ...Option) *Hub {
+	h := &Hub{
+		sc:               sc,
+		l:                l,
+		maxClients:       _defaultMaxClients,
+		maxSubscriptions: _defaultMaxSubscriptions,
+		maxQueries:       _defaultMaxQueries,
+		maxCountOfBlocks: _defaultMaxCountOfBlocks,
+		workers:          _defaultWorkers,
+		computeTimeout:   _defaultComputeTimeout,
+		pongWait:         _defaultPongWait,
+		clients:          make(map[*Client]struct{}),
+		queries:          make(map[query]int),
+		notify:           make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.pool = make(chan struct{}, h.workers)
+
+	return h
+}
+
+// Starting sending of updates.
+func (h *Hub) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.done = make(chan struct{})
+
+	go h.run(ctx)
+}
+
+// Stopping sending of updates and disconnecting all clients.
+func (h *Hub) Stop() {
+	if h.cancel == nil {
+		return
+	}
+
+	h.cancel()
+	<-h.done
+
+	h.mu.Lock()
+	clients := make([]*Client, 0, len(h.clients))
+
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		c.Close()
+	}
+}
+
+// Notifying hub about new block, it's listener of follower.
+// Blocks which come during update are joined, so slow update doesn't block follower.
+func (h *Hub) Notify(_ *big.Int) {
+	select {
+	case h.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Getting count of connected clients.
+func (h *Hub) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.clients)
+}
+
+func (h *Hub) run(ctx context.Context) {
+	defer close(h.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.notify:
+			h.update(ctx)
+		}
+	}
+}
+
+// Computing results of all subscriptions in pool of workers and sending changed results.
+func (h *Hub) update(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, q := range h.distinctQueries() {
+		h.pool <- struct{}{}
+
+		wg.Add(1)
+
+		go func(q query) {
+			defer wg.Done()
+			defer func() { <-h.pool }()
+
+			h.updateQuery(ctx, q)
+		}(q)
+	}
+
+	wg.Wait()
+}
+
+// Computing the first result of new subscription in pool of workers, so reading of client isn't blocked by it.
+// Invalid query is found by use case, so subscription is removed if its result can't be computed.
+func (h *Hub) computeFirst(ctx context.Context, c *Client, id string, sub *subscription) {
+	select {
+	case h.pool <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	defer func() { <-h.pool }()
+
+	msg, err := h.compute(ctx, sub.query)
+	if err == nil {
+		c.first(id, sub, msg)
+
+		return
+	}
+
+	if c.drop(id, sub) {
+		h.release(sub.query)
+		c.reply(&Message{Type: TypeError, ID: id, Error: h.errorText(err)})
+	}
+}
+
+// Computing result of query and sending it to clients subscribed on it.
+func (h *Hub) updateQuery(ctx context.Context, q query) {
+	msg, err := h.compute(ctx, q)
+	if err != nil {
+		if ctx.Err() == nil {
+			h.l.Error("ws - Hub - updateQuery", sl.Err(err))
+		}
+
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for c := range h.clients {
+		c.deliver(q, msg)
+	}
+}
+
+// Getting distinct queries of subscriptions.
+func (h *Hub) distinctQueries() []query {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	res := make([]query, 0, len(h.queries))
+	for q := range h.queries {
+		res = append(res, q)
+	}
+
+	return res
+}
+
+// Counting new subscription on query, error is returned if query can't be computed on every block.
+func (h *Hub) acquire(q query) error {
+	if q.CountOfBlocks > h.maxCountOfBlocks {
+		return ErrWindowTooLarge
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.queries[q] == 0 && len(h.queries) >= h.maxQueries {
+		return ErrTooManyQueries
+	}
+
+	h.queries[q]++
+
+	return nil
+}
+
+// Removing subscription on query, query isn't computed after its last subscription is removed.
+func (h *Hub) release(q query) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.queries[q]--; h.queries[q] <= 0 {
+		delete(h.queries, q)
+	}
+}
+
+// Getting current result of query in format of update.
+// Result is computed with timeout, so slow upstream doesn't stop updates of other queries.
+func (h *Hub) compute(ctx context.Context, q query) (*Message, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.computeTimeout)
+	defer cancel()
+
+	changesQuery := entity.ChangesQuery{CountOfBlocks: q.CountOfBlocks, Metric: q.Metric}
+
+	if q.Limit == 0 {
+		res, err := h.sc.GetAddressWithBiggestChange(ctx, changesQuery)
+		if err != nil {
+			return nil, fmt.Errorf("Hub - compute - h.sc.GetAddressWithBiggestChange: %w", err)
+		}
+
+		return &Message{Type: TypeUpdate, Block: res.LastBlock, Change: res}, nil
+	}
+
+	res, err := h.sc.GetTopChanges(ctx, changesQuery, q.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("Hub - compute - h.sc.GetTopChanges: %w", err)
+	}
+
+	return &Message{Type: TypeUpdate, Block: res.LastBlock, Top: res}, nil
+}
+
+// Registering new client, error is returned if limit of clients is reached.
+func (h *Hub) newClient() (*Client, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.clients) >= h.maxClients {
+		return nil, ErrTooManyClients
+	}
+
+	c := newClient(h)
+	h.clients[c] = struct{}{}
+
+	connectedClients.Inc()
+
+	return c, nil
+}
+
+func (h *Hub) removeClient(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		connectedClients.Dec()
+	}
+}
@@ -0,0 +1,353 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	mock "github.com/egor-denisov/biggest-change/internal/usecase/mocks"
+	"github.com/egor-denisov/biggest-change/pkg/logger"
+	"github.com/go-playground/assert"
+	"github.com/golang/mock/gomock"
+	"golang.org/x/net/websocket"
+)
+
+// Starting server of hub with fake use case as upstream.
+func startHub(t *testing.T, opts ...Option) (*Hub, *mock.MockStatsOfChanging, string) {
+	t.Helper()
+
+	c := gomock.NewController(t)
+	m := mock.NewMockStatsOfChanging(c)
+	h := New(logger.SetupLogger("debug"), m, opts...)
+
+	s := httptest.NewServer(h.Handler())
+	t.Cleanup(func() {
+		h.Stop()
+		s.Close()
+	})
+
+	return h, m, "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+
+	conn, err := websocket.Dial(url, "", "http://localhost/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func send(t *testing.T, conn *websocket.Conn, msg string) {
+	t.Helper()
+
+	if err := websocket.Message.Send(conn, msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func receive(t *testing.T, conn *websocket.Conn) *Message {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	var res Message
+	if err := websocket.JSON.Receive(conn, &res); err != nil {
+		t.Fatal(err)
+	}
+
+	return &res
+}
+
+// Waiting until count of clients of hub is equal to expected.
+func waitClients(t *testing.T, h *Hub, expected int) {
+	t.Helper()
+
+	for deadline := time.Now().Add(2 * time.Second); h.Len() != expected; {
+		if time.Now().After(deadline) {
+			t.Fatalf("count of clients is %d, expected %d", h.Len(), expected)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func biggestChange(address, amount, block string) *entity.BiggestChange {
+	return &entity.BiggestChange{Address: address, Amount: amount, LastBlock: block}
+}
+
+func Test_Hub_Updates(t *testing.T) {
+	h, m, url := startHub(t)
+	conn := dial(t, url)
+	ctx := context.Background()
+
+	query := entity.ChangesQuery{CountOfBlocks: 2, Metric: entity.MetricNet}
+	gomock.InOrder(
+		m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), query).Return(biggestChange("0x1", "0x10", "0xc8"), nil),
+		m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), query).Return(biggestChange("0x1", "0x10", "0xc9"), nil),
+		m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), query).Return(biggestChange("0x2", "0x20", "0xca"), nil),
+	)
+
+	// Current result is sent on subscription
+	send(t, conn, `{"type": "subscribe", "id": "a", "query": {"countOfBlocks": 2, "metric": "net"}}`)
+	assert.Equal(t, &Message{Type: TypeUpdate, ID: "a", Block: "0xc8", Change: biggestChange("0x1", "0x10", "0xc8")},
+		receive(t, conn))
+
+	// Block 0xc9 doesn't change result, so only block 0xca is sent
+	h.update(ctx)
+	h.update(ctx)
+	assert.Equal(t, &Message{Type: TypeUpdate, ID: "a", Block: "0xca", Change: biggestChange("0x2", "0x20", "0xca")},
+		receive(t, conn))
+
+	top := &entity.TopChanges{Changes: []*entity.AddressChange{{Address: "0x3", Amount: "0x30"}}, LastBlock: "0xca"}
+	m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{}, uint(1)).Return(top, nil).Times(2)
+
+	send(t, conn, `{"type": "subscribe", "id": "b", "query": {"limit": 1}}`)
+	assert.Equal(t, &Message{Type: TypeUpdate, ID: "b", Block: "0xca", Top: top}, receive(t, conn))
+
+	second := dial(t, url)
+	send(t, second, `{"type": "subscribe", "id": "c", "query": {"limit": 1}}`)
+	assert.Equal(t, &Message{Type: TypeUpdate, ID: "c", Block: "0xca", Top: top}, receive(t, second))
+
+	send(t, conn, `{"type": "unsubscribe", "id": "a"}`)
+	send(t, conn, `{"type": "unsubscribe", "id": "a"}`)
+	assert.Equal(t, &Message{Type: TypeError, ID: "a", Error: ErrSubscriptionNotFound.Error()}, receive(t, conn))
+
+	// Query of both subscriptions is computed once and unsubscribed query isn't computed
+	m.EXPECT().GetTopChanges(gomock.Any(), entity.ChangesQuery{}, uint(1)).Return(top, nil)
+
+	h.update(ctx)
+}
+
+func Test_Hub_Errors(t *testing.T) {
+	_, m, url := startHub(t, MaxSubscriptions(1))
+	conn := dial(t, url)
+
+	m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{Metric: "volume"}).
+		Return(nil, fmt.Errorf("StatsOfChangingUseCase - resolveMetric: %w", entity.ErrInvalidMetric))
+	m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{}).
+		Return(nil, fmt.Errorf("StatsOfChangingUseCase - getWindow: %w", entity.ErrServiceResponse))
+	m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 1}).
+		Return(biggestChange("0x1", "0x10", "0xc8"), nil)
+
+	for _, test := range testsErrors {
+		t.Run(test.name, func(t *testing.T) {
+			send(t, conn, test.request)
+
+			res := receive(t, conn)
+
+			assert.Equal(t, test.expectedMessage, res)
+		})
+	}
+}
+
+var testsErrors = []struct {
+	name            string
+	request         string
+	expectedMessage *Message
+}{
+	{
+		name:            "Invalid metric",
+		request:         `{"type": "subscribe", "id": "a", "query": {"metric": "volume"}}`,
+		expectedMessage: &Message{Type: TypeError, ID: "a", Error: "invalid metric"},
+	},
+	{
+		name:            "Internal error",
+		request:         `{"type": "subscribe", "id": "a"}`,
+		expectedMessage: &Message{Type: TypeError, ID: "a", Error: "internal server error"},
+	},
+	{
+		name:            "Unknown type",
+		request:         `{"type": "ping"}`,
+		expectedMessage: &Message{Type: TypeError, Error: "unknown type of message"},
+	},
+	{
+		name:            "Invalid message",
+		request:         `{"type": `,
+		expectedMessage: &Message{Type: TypeError, Error: "invalid message: unexpected end of JSON input"},
+	},
+	{
+		name:    "Subscribed",
+		request: `{"type": "subscribe", "id": "a", "query": {"countOfBlocks": 1}}`,
+		expectedMessage: &Message{
+			Type:   TypeUpdate,
+			ID:     "a",
+			Block:  "0xc8",
+			Change: biggestChange("0x1", "0x10", "0xc8"),
+		},
+	},
+	{
+		name:            "Subscription exists",
+		request:         `{"type": "subscribe", "id": "a", "query": {"countOfBlocks": 1}}`,
+		expectedMessage: &Message{Type: TypeError, ID: "a", Error: "subscription already exists"},
+	},
+	{
+		name:            "Too many subscriptions",
+		request:         `{"type": "subscribe", "id": "b", "query": {"countOfBlocks": 1}}`,
+		expectedMessage: &Message{Type: TypeError, ID: "b", Error: "too many subscriptions"},
+	},
+}
+
+func Test_Hub_MaxQueries(t *testing.T) {
+	_, m, url := startHub(t, MaxQueries(1), MaxCountOfBlocks(10))
+	conn := dial(t, url)
+	second := dial(t, url)
+
+	m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 1}).
+		Return(biggestChange("0x1", "0x10", "0xc8"), nil).Times(2)
+	m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 2}).
+		Return(biggestChange("0x2", "0x20", "0xc8"), nil)
+
+	send(t, conn, `{"type": "subscribe", "id": "a", "query": {"countOfBlocks": 11}}`)
+	assert.Equal(t, &Message{Type: TypeError, ID: "a", Error: ErrWindowTooLarge.Error()}, receive(t, conn))
+
+	send(t, conn, `{"type": "subscribe", "id": "a", "query": {"countOfBlocks": 1}}`)
+	assert.Equal(t, TypeUpdate, receive(t, conn).Type)
+	// The same query of another client isn't counted again
+	send(t, second, `{"type": "subscribe", "id": "a", "query": {"countOfBlocks": 1}}`)
+	assert.Equal(t, TypeUpdate, receive(t, second).Type)
+
+	send(t, conn, `{"type": "subscribe", "id": "b", "query": {"countOfBlocks": 2}}`)
+	assert.Equal(t, &Message{Type: TypeError, ID: "b", Error: ErrTooManyQueries.Error()}, receive(t, conn))
+
+	// Query is released by the last of its subscriptions, error of second unsubscribe means that the first one is done
+	send(t, conn, `{"type": "unsubscribe", "id": "a"}`)
+	send(t, second, `{"type": "unsubscribe", "id": "a"}`)
+	send(t, second, `{"type": "unsubscribe", "id": "a"}`)
+	assert.Equal(t, TypeError, receive(t, second).Type)
+
+	send(t, conn, `{"type": "subscribe", "id": "b", "query": {"countOfBlocks": 2}}`)
+	assert.Equal(t, &Message{Type: TypeUpdate, ID: "b", Block: "0xc8", Change: biggestChange("0x2", "0x20", "0xc8")},
+		receive(t, conn))
+}
+
+func Test_Hub_ComputeTimeout(t *testing.T) {
+	h, m, url := startHub(t, Workers(2), ComputeTimeout(100*time.Millisecond))
+	conn := dial(t, url)
+	ctx := context.Background()
+
+	slow := entity.ChangesQuery{CountOfBlocks: 1}
+	fast := entity.ChangesQuery{CountOfBlocks: 2}
+
+	gomock.InOrder(
+		m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), slow).Return(biggestChange("0x1", "0x10", "0xc8"), nil),
+		// Upstream doesn't answer until timeout of compute
+		m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), slow).
+			DoAndReturn(func(ctx context.Context, _ entity.ChangesQuery) (*entity.BiggestChange, error) {
+				<-ctx.Done()
+
+				return nil, ctx.Err()
+			}),
+	)
+	gomock.InOrder(
+		m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), fast).Return(biggestChange("0x2", "0x20", "0xc8"), nil),
+		m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), fast).Return(biggestChange("0x3", "0x30", "0xc9"), nil),
+	)
+
+	send(t, conn, `{"type": "subscribe", "id": "slow", "query": {"countOfBlocks": 1}}`)
+	assert.Equal(t, "slow", receive(t, conn).ID)
+	send(t, conn, `{"type": "subscribe", "id": "fast", "query": {"countOfBlocks": 2}}`)
+	assert.Equal(t, "fast", receive(t, conn).ID)
+
+	// Slow query is stopped by timeout and doesn't block update of other query
+	start := time.Now()
+
+	h.update(ctx)
+	assert.Equal(t, true, time.Since(start) < time.Second)
+	assert.Equal(t, &Message{Type: TypeUpdate, ID: "fast", Block: "0xc9", Change: biggestChange("0x3", "0x30", "0xc9")},
+		receive(t, conn))
+}
+
+func Test_Hub_SlowSubscribe(t *testing.T) {
+	_, m, url := startHub(t)
+	conn := dial(t, url)
+
+	release := make(chan struct{})
+
+	m.EXPECT().GetAddressWithBiggestChange(gomock.Any(), entity.ChangesQuery{CountOfBlocks: 1}).
+		DoAndReturn(func(context.Context, entity.ChangesQuery) (*entity.BiggestChange, error) {
+			<-release
+
+			return biggestChange("0x1", "0x10", "0xc8"), nil
+		})
+
+	// Messages are read while the first result of subscription is computed
+	send(t, conn, `{"type": "subscribe", "id": "a", "query": {"countOfBlocks": 1}}`)
+	send(t, conn, `{"type": "unsubscribe", "id": "b"}`)
+	assert.Equal(t, &Message{Type: TypeError, ID: "b", Error: ErrSubscriptionNotFound.Error()}, receive(t, conn))
+
+	close(release)
+	assert.Equal(t, &Message{Type: TypeUpdate, ID: "a", Block: "0xc8", Change: biggestChange("0x1", "0x10", "0xc8")},
+		receive(t, conn))
+}
+
+func Test_Hub_MaxClients(t *testing.T) {
+	h, _, url := startHub(t, MaxClients(1))
+
+	dial(t, url)
+	waitClients(t, h, 1)
+
+	_, err := websocket.Dial(url, "", "http://localhost/")
+	assert.NotEqual(t, nil, err)
+}
+
+func Test_Hub_KeepAlive(t *testing.T) {
+	h, _, url := startHub(t, PongWait(300*time.Millisecond))
+
+	// Pings are answered only while client reads messages
+	alive := dial(t, url)
+	go func() {
+		var msg Message
+
+		for {
+			if err := websocket.JSON.Receive(alive, &msg); err != nil {
+				return
+			}
+		}
+	}()
+
+	dial(t, url)
+	waitClients(t, h, 2)
+
+	waitClients(t, h, 1)
+	time.Sleep(time.Second)
+	assert.Equal(t, 1, h.Len())
+}
+
+func Test_Client_Backpressure(t *testing.T) {
+	h := New(logger.SetupLogger("debug"), nil)
+	c := newClient(h)
+	q := query{CountOfBlocks: 1}
+
+	c.subs["a"] = &subscription{query: q}
+	c.subs["b"] = &subscription{query: query{Limit: 1}}
+
+	// Update which isn't sent is replaced by the latest one
+	c.deliver(q, &Message{Type: TypeUpdate, Change: biggestChange("0x1", "0x10", "0xc8")})
+	c.reply(&Message{Type: TypeError, ID: "c"})
+	c.deliver(q, &Message{Type: TypeUpdate, Change: biggestChange("0x2", "0x20", "0xc9")})
+
+	assert.Equal(t, []*Message{
+		{Type: TypeUpdate, ID: "a", Change: biggestChange("0x2", "0x20", "0xc9")},
+		{Type: TypeError, ID: "c"},
+	}, c.take())
+
+	// Client which doesn't read replies is disconnected
+	for i := 0; i <= _maxPending; i++ {
+		c.reply(&Message{Type: TypeError})
+	}
+
+	select {
+	case <-c.done:
+	default:
+		t.Fatal("client isn't disconnected")
+	}
+}
@@ -0,0 +1,66 @@
+package ws
+
+import (
+	"strings"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Types of messages from client.
+const (
+	TypeSubscribe   = "subscribe"
+	TypeUnsubscribe = "unsubscribe"
+)
+
+// Types of messages to client.
+const (
+	TypeUpdate = "update"
+	TypeError  = "error"
+)
+
+// Message from client, subscription is identified by id chosen by client.
+type request struct {
+	Type  string `json:"type"`
+	ID    string `json:"id"`
+	Query query  `json:"query"`
+}
+
+// Window and metric of subscription, if Limit is set top of changes is sent instead of the biggest change.
+type query struct {
+	CountOfBlocks uint   `json:"countOfBlocks"`
+	Metric        string `json:"metric"`
+	Limit         uint   `json:"limit"`
+}
+
+// Message to client, update has either the biggest change or top of changes.
+type Message struct {
+	Type   string                `json:"type"`
+	ID     string                `json:"id,omitempty"`
+	Block  string                `json:"block,omitempty"`
+	Change *entity.BiggestChange `json:"change,omitempty"`
+	Top    *entity.TopChanges    `json:"top,omitempty"`
+	Error  string                `json:"error,omitempty"`
+}
+
+// Getting copy of update for subscription.
+func (m *Message) to(id string) *Message {
+	res := *m
+	res.ID = id
+
+	return &res
+}
+
+// Getting ranked addresses with amounts, update is sent only if they are changed by new block.
+func (m *Message) signature() string {
+	if m.Change != nil {
+		return m.Change.Address + ":" + m.Change.Amount
+	}
+
+	var b strings.Builder
+
+	for _, change := range m.Top.Changes {
+		b.WriteString(change.Address + ":" + change.Amount + ",")
+	}
+
+	return b.String()
+}
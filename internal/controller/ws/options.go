@@ -0,0 +1,47 @@
+package ws
+
+import "time"
+
+type Option func(*Hub)
+
+func MaxClients(maxClients int) Option {
+	return func(h *Hub) {
+		h.maxClients = maxClients
+	}
+}
+
+func MaxSubscriptions(maxSubscriptions int) Option {
+	return func(h *Hub) {
+		h.maxSubscriptions = maxSubscriptions
+	}
+}
+
+func MaxQueries(maxQueries int) Option {
+	return func(h *Hub) {
+		h.maxQueries = maxQueries
+	}
+}
+
+func MaxCountOfBlocks(maxCountOfBlocks uint) Option {
+	return func(h *Hub) {
+		h.maxCountOfBlocks = maxCountOfBlocks
+	}
+}
+
+func Workers(workers int) Option {
+	return func(h *Hub) {
+		h.workers = workers
+	}
+}
+
+func ComputeTimeout(computeTimeout time.Duration) Option {
+	return func(h *Hub) {
+		h.computeTimeout = computeTimeout
+	}
+}
+
+func PongWait(pongWait time.Duration) Option {
+	return func(h *Hub) {
+		h.pongWait = pongWait
+	}
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var _addressRegexp = regexp.MustCompile(`^0x[0-9a-f]{40}$`)
+
+// Validating address and bringing it to lower case like addresses from web api.
+func NormalizeAddress(addr string) (string, error) {
+	res := strings.ToLower(strings.TrimSpace(addr))
+	if !_addressRegexp.MatchString(res) {
+		return "", fmt.Errorf("NormalizeAddress - %q: %w", addr, ErrInvalidAddress)
+	}
+
+	return res, nil
+}
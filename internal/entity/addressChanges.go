@@ -0,0 +1,24 @@
+package entity
+
+// @Description Изменение баланса адреса в блоке .
+type BlockChange struct {
+	Block      string `json:"block"`
+	Amount     string `json:"amount"`
+	IsRecieved bool   `json:"isRecieved"`
+}
+
+// @Description История изменений баланса адреса .
+type AddressChanges struct {
+	Address        string         `json:"address"`
+	Changes        []*BlockChange `json:"changes"`
+	Total          string         `json:"total"`
+	IsRecieved     bool           `json:"isRecieved"`
+	FirstBlock     string         `json:"firstBlock"`
+	LastBlock      string         `json:"lastBlock"`
+	FirstBlockTime uint64         `json:"firstBlockTimestamp"`
+	LastBlockTime  uint64         `json:"lastBlockTimestamp"`
+	CountOfBlocks  int64          `json:"countOfBlocks"`
+	Anchor         string         `json:"anchor"`
+	AnchorBlock    string         `json:"anchorBlock"`
+	Token          *Token         `json:"token,omitempty"`
+}
@@ -0,0 +1,10 @@
+package entity
+
+// @Description Фильтры адресов, применённые при поиске .
+type AddressFilters struct {
+	Include           []string `json:"include,omitempty"`
+	Exclude           []string `json:"exclude,omitempty"`
+	Categories        []string `json:"categories,omitempty"`
+	ExcludeCategories []string `json:"excludeCategories,omitempty"`
+	ExcludedByConfig  int      `json:"excludedByConfig,omitempty"`
+}
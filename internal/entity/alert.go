@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// Rule of alert, it fires when change of address in window reaches threshold.
+// Threshold is set in wei (or in base units of token) as decimal or hex number.
+// If Address or Category is set, only this address or addresses with labels of this category are checked.
+//
+// @Description Правило оповещения .
+type AlertRule struct {
+	ID            string `json:"id"`
+	Threshold     string `json:"threshold"`
+	Direction     string `json:"direction"`
+	CountOfBlocks uint   `json:"countOfBlocks,omitempty"`
+	Address       string `json:"address,omitempty"`
+	Category      string `json:"category,omitempty"`
+	Webhook       string `json:"webhook"`
+}
+
+// Directions of change which fire alert.
+const (
+	DirectionIn  = "in"
+	DirectionOut = "out"
+	DirectionAny = "any"
+)
+
+// @Description Оповещение о превышении порога, amount и threshold в hex, как и остальные суммы .
+type Alert struct {
+	Rule          string    `json:"rule"`
+	Address       string    `json:"address"`
+	Label         *Label    `json:"label,omitempty"`
+	Amount        string    `json:"amount"`
+	Threshold     string    `json:"threshold"`
+	IsRecieved    bool      `json:"isRecieved"`
+	FirstBlock    string    `json:"firstBlock"`
+	LastBlock     string    `json:"lastBlock"`
+	CountOfBlocks int64     `json:"countOfBlocks"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
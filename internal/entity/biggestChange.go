@@ -1,10 +1,42 @@
 package entity
 
+import "time"
+
 // @Description Наибольшее изменение .
 type BiggestChange struct {
-	Address       string `json:"address"`
-	Amount        string `json:"amount"`
-	LastBlock     string `json:"lastBlock"`
-	CountOfBlocks int64  `json:"countOfBlocks"`
-	IsRecieved    bool   `json:"isRecieved"`
+	Address            string                     `json:"address"`
+	Label              *Label                     `json:"label,omitempty"`
+	Entity             string                     `json:"entity,omitempty"`
+	Addresses          []string                   `json:"addresses,omitempty"`
+	Amount             string                     `json:"amount"`
+	StartBalance       string                     `json:"startBalance,omitempty"`
+	EndBalance         string                     `json:"endBalance,omitempty"`
+	PercentChange      *float64                   `json:"percentChange,omitempty"`
+	FirstBlock         string                     `json:"firstBlock"`
+	LastBlock          string                     `json:"lastBlock"`
+	FirstBlockTime     uint64                     `json:"firstBlockTimestamp"`
+	LastBlockTime      uint64                     `json:"lastBlockTimestamp"`
+	Metric             string                     `json:"metric"`
+	GroupBy            string                     `json:"groupBy,omitempty"`
+	CountOfBlocks      int64                      `json:"countOfBlocks"`
+	IsRecieved         bool                       `json:"isRecieved"`
+	IsNewContract      bool                       `json:"isNewContract"`
+	FailedTransactions int64                      `json:"failedTransactions"`
+	BurnedFees         string                     `json:"burnedFees"`
+	Anchor             string                     `json:"anchor"`
+	AnchorBlock        string                     `json:"anchorBlock"`
+	Token              *Token                     `json:"token,omitempty"`
+	Filters            *AddressFilters            `json:"filters,omitempty"`
+	Transactions       []*ContributingTransaction `json:"transactions,omitempty"`
+	ComputedAt         *time.Time                 `json:"computedAt,omitempty"`
+	Stale              bool                       `json:"stale,omitempty"`
+}
+
+// @Description Транзакция, которая изменила баланс адреса .
+type ContributingTransaction struct {
+	Hash       string `json:"hash"`
+	Block      string `json:"block"`
+	Value      string `json:"value"`
+	Fee        string `json:"fee"`
+	IsRecieved bool   `json:"isRecieved"`
 }
@@ -1,6 +1,24 @@
 package entity
 
+import "math/big"
+
 // @Description Блок .
 type Block struct {
-	Transactions []Transaction `json:"transactions"`
+	Number            *big.Int            `json:"number"`
+	Hash              string              `json:"hash"`
+	ParentHash        string              `json:"parentHash"`
+	Timestamp         uint64              `json:"timestamp"`
+	Miner             string              `json:"miner"`
+	BaseFeePerGas     *big.Int            `json:"baseFeePerGas"`
+	Transactions      []*Transaction      `json:"transactions"`
+	Withdrawals       []*Withdrawal       `json:"withdrawals"`
+	InternalTransfers []*InternalTransfer `json:"internalTransfers"`
+}
+
+// @Description Заголовок блока без транзакций .
+type BlockHeader struct {
+	Number     *big.Int `json:"number"`
+	Hash       string   `json:"hash"`
+	ParentHash string   `json:"parentHash"`
+	Timestamp  uint64   `json:"timestamp"`
 }
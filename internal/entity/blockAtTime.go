@@ -0,0 +1,8 @@
+package entity
+
+// @Description Последний блок на момент времени .
+type BlockAtTime struct {
+	Number    string `json:"number"`
+	Hash      string `json:"hash"`
+	Timestamp uint64 `json:"timestamp"`
+}
@@ -0,0 +1,55 @@
+package entity
+
+import "time"
+
+// Parameters of window in which changes of addresses are searched.
+// If FromBlock or ToBlock is nil, window is anchored on the current block.
+// Since and Duration describe window by time of blocks instead of numbers.
+// Metric is value by which addresses are ranked, by default it's net change.
+// If Token is set, changes of ERC-20 token balances are searched instead of ether.
+// Anchor is tag of block which bounds the window, by default it's configured in use case.
+// If Explain is set, transactions which made the biggest change are returned too.
+// If Include is set, only these addresses are ranked, addresses from Exclude are never ranked.
+// Categories and ExcludeCategories filter addresses by category of their labels in the same way.
+// GroupBy is set to entity to rank sums of changes of addresses of one entity.
+// If Balances is set, balances of found addresses before and after the window are returned too.
+type ChangesQuery struct {
+	CountOfBlocks     uint
+	FromBlock         *uint64
+	ToBlock           *uint64
+	Token             string
+	Anchor            string
+	Since             *time.Time
+	Duration          time.Duration
+	Metric            string
+	Explain           bool
+	Include           []string
+	Exclude           []string
+	Categories        []string
+	ExcludeCategories []string
+	GroupBy           string
+	Balances          bool
+}
+
+// Metrics by which addresses can be ranked.
+const (
+	MetricNet      = "net"
+	MetricInflow   = "inflow"
+	MetricOutflow  = "outflow"
+	MetricGross    = "gross"
+	MetricTxCount  = "tx_count"
+	MetricRelative = "relative"
+)
+
+// Ways of grouping addresses before ranking.
+const (
+	GroupByAddress = "address"
+	GroupByEntity  = "entity"
+)
+
+// Tags of blocks on which window can be anchored.
+const (
+	AnchorLatest    = "latest"
+	AnchorSafe      = "safe"
+	AnchorFinalized = "finalized"
+)
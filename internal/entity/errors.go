@@ -7,4 +7,45 @@ var (
 	ErrStringIsNotHex          = errors.New("string is not a hex string")
 	ErrTooMuchRequestToService = errors.New("too many requests to service")
 	ErrInternalServer          = errors.New("internal server error")
+	ErrInvalidBlockRange       = errors.New("invalid block range")
+	ErrReceiptNotFound         = errors.New("transaction receipt not found")
+	ErrInvalidAddress          = errors.New("invalid address")
+	ErrNotERC20Token           = errors.New("contract is not an ERC-20 token")
+	ErrServiceResponse         = errors.New("service returned error")
+	ErrChainReorganized        = errors.New("chain is being reorganized")
+	ErrInvalidAnchor           = errors.New("invalid anchor")
+	ErrBlockNotFound           = errors.New("block not found")
+	ErrTimeBeforeGenesis       = errors.New("time is before first block")
+	ErrInvalidMetric           = errors.New("invalid metric")
+	ErrInvalidGroupBy          = errors.New("invalid grouping")
+	ErrInvalidCluster          = errors.New("invalid cluster")
+	ErrClusterNotFound         = errors.New("cluster not found")
+	ErrInvalidAlertRule        = errors.New("invalid alert rule")
+	ErrAlertRuleNotFound       = errors.New("alert rule not found")
+	ErrInvalidLimit            = errors.New("invalid limit")
 )
+
+// Errors which are caused by invalid parameters of request.
+var _requestErrors = []error{
+	ErrInvalidBlockRange,
+	ErrInvalidAddress,
+	ErrNotERC20Token,
+	ErrInvalidAnchor,
+	ErrTimeBeforeGenesis,
+	ErrInvalidMetric,
+	ErrInvalidGroupBy,
+	ErrInvalidCluster,
+	ErrInvalidAlertRule,
+	ErrInvalidLimit,
+}
+
+// Getting error caused by invalid parameters of request, or nil if err isn't such error.
+func RequestError(err error) error {
+	for _, e := range _requestErrors {
+		if errors.Is(err, e) {
+			return e
+		}
+	}
+
+	return nil
+}
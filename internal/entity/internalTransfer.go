@@ -0,0 +1,10 @@
+package entity
+
+import "math/big"
+
+// @Description Перевод эфира внутри вызова контракта .
+type InternalTransfer struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Value *big.Int `json:"value"`
+}
@@ -0,0 +1,7 @@
+package entity
+
+// @Description Метка адреса .
+type Label struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
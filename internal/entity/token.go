@@ -0,0 +1,19 @@
+package entity
+
+import "math/big"
+
+// @Description ERC-20 токен .
+type Token struct {
+	Address  string `json:"address"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// @Description Перевод ERC-20 токена .
+type TokenTransfer struct {
+	Token       string   `json:"token"`
+	From        string   `json:"from"`
+	To          string   `json:"to"`
+	Value       *big.Int `json:"value"`
+	BlockNumber *big.Int `json:"blockNumber"`
+	TxHash      string   `json:"transactionHash"`
+}
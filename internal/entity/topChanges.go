@@ -0,0 +1,33 @@
+package entity
+
+// @Description Изменение баланса адреса .
+type AddressChange struct {
+	Address       string   `json:"address"`
+	Label         *Label   `json:"label,omitempty"`
+	Entity        string   `json:"entity,omitempty"`
+	Addresses     []string `json:"addresses,omitempty"`
+	Amount        string   `json:"amount"`
+	StartBalance  string   `json:"startBalance,omitempty"`
+	EndBalance    string   `json:"endBalance,omitempty"`
+	PercentChange *float64 `json:"percentChange,omitempty"`
+	IsRecieved    bool     `json:"isRecieved"`
+	IsNewContract bool     `json:"isNewContract"`
+}
+
+// @Description Рейтинг наибольших изменений .
+type TopChanges struct {
+	Changes            []*AddressChange `json:"changes"`
+	FirstBlock         string           `json:"firstBlock"`
+	LastBlock          string           `json:"lastBlock"`
+	FirstBlockTime     uint64           `json:"firstBlockTimestamp"`
+	LastBlockTime      uint64           `json:"lastBlockTimestamp"`
+	Metric             string           `json:"metric"`
+	GroupBy            string           `json:"groupBy,omitempty"`
+	CountOfBlocks      int64            `json:"countOfBlocks"`
+	FailedTransactions int64            `json:"failedTransactions"`
+	BurnedFees         string           `json:"burnedFees"`
+	Anchor             string           `json:"anchor"`
+	AnchorBlock        string           `json:"anchorBlock"`
+	Token              *Token           `json:"token,omitempty"`
+	Filters            *AddressFilters  `json:"filters,omitempty"`
+}
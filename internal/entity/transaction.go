@@ -4,9 +4,60 @@ import "math/big"
 
 // @Description Транзакция .
 type Transaction struct {
-	From     string   `json:"from"`
-	Gas      *big.Int `json:"gas"`
-	GasPrice *big.Int `json:"gasPrice"`
-	To       string   `json:"to"`
-	Value    *big.Int `json:"value"`
+	Hash              string   `json:"hash"`
+	From              string   `json:"from"`
+	Gas               *big.Int `json:"gas"`
+	GasPrice          *big.Int `json:"gasPrice"`
+	GasUsed           *big.Int `json:"gasUsed"`
+	EffectiveGasPrice *big.Int `json:"effectiveGasPrice"`
+	Status            uint64   `json:"status"`
+	To                string   `json:"to"`
+	ContractAddress   string   `json:"contractAddress"`
+	Value             *big.Int `json:"value"`
+}
+
+// Fee which was paid by sender of transaction.
+// Without receipt data the fee is estimated by gas limit and gas price.
+func (t *Transaction) Fee() *big.Int {
+	if t.GasUsed != nil && t.EffectiveGasPrice != nil {
+		return new(big.Int).Mul(t.GasUsed, t.EffectiveGasPrice)
+	}
+
+	return new(big.Int).Mul(t.Gas, t.GasPrice)
+}
+
+// Checking that transaction was reverted.
+// Without receipt data the transaction is considered successful.
+func (t *Transaction) Failed() bool {
+	return t.GasUsed != nil && t.Status == 0
+}
+
+// Part of fee which was burned by base fee of block.
+// Blocks before London fork don't have base fee, so nothing is burned.
+func (t *Transaction) BurnedFee(baseFeePerGas *big.Int) *big.Int {
+	if baseFeePerGas == nil {
+		return new(big.Int)
+	}
+
+	gasUsed := t.GasUsed
+	if gasUsed == nil {
+		gasUsed = t.Gas
+	}
+
+	return new(big.Int).Mul(gasUsed, baseFeePerGas)
+}
+
+// Checking that transaction creates contract.
+func (t *Transaction) CreatesContract() bool {
+	return t.To == ""
+}
+
+// Address which receives value of transaction.
+// For contract creation it is address of created contract, which is empty without receipt data.
+func (t *Transaction) Recipient() string {
+	if t.CreatesContract() {
+		return t.ContractAddress
+	}
+
+	return t.To
 }
@@ -0,0 +1,9 @@
+package entity
+
+import "math/big"
+
+// @Description Вывод средств с beacon-chain .
+type Withdrawal struct {
+	Address string   `json:"address"`
+	Amount  *big.Int `json:"amount"`
+}
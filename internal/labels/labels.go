@@ -0,0 +1,109 @@
+// Package labels implements registry of address labels, which is loaded from local YAML or CSV file.
+package labels
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/egor-denisov/biggest-change/internal/records"
+)
+
+var (
+	ErrUnknownFormat = records.ErrUnknownFormat
+	ErrInvalidLabel  = errors.New("invalid label")
+)
+
+type Store struct {
+	path      string
+	mu        sync.RWMutex
+	labels    map[string]*entity.Label
+	listeners []func()
+}
+
+type labelRecord struct {
+	Address  string `yaml:"address"`
+	Name     string `yaml:"name"`
+	Category string `yaml:"category"`
+}
+
+// New store reads labels from path, store without path has no labels.
+func New(path string) *Store {
+	return &Store{
+		path:   path,
+		labels: make(map[string]*entity.Label),
+	}
+}
+
+// Reading labels from file again, labels are kept if file can't be read.
+func (s *Store) Reload() error {
+	if s.path == "" {
+		return nil
+	}
+
+	rs, err := records.Read(s.path, labelFromRow)
+	if err != nil {
+		return fmt.Errorf("LabelStore - Reload - records.Read: %w", err)
+	}
+
+	labels := make(map[string]*entity.Label, len(rs))
+
+	for i, r := range rs {
+		addr, err := entity.NormalizeAddress(r.Address)
+		if err != nil || r.Name == "" {
+			return fmt.Errorf("LabelStore - Reload - record %d: %w", i+1, ErrInvalidLabel)
+		}
+
+		labels[addr] = &entity.Label{
+			Name:     strings.TrimSpace(r.Name),
+			Category: strings.ToLower(strings.TrimSpace(r.Category)),
+		}
+	}
+
+	s.mu.Lock()
+	s.labels = labels
+	s.mu.Unlock()
+
+	for _, listener := range s.listeners {
+		listener()
+	}
+
+	return nil
+}
+
+// Adding listener which is called after labels are reloaded, listeners must be added before reloads.
+func (s *Store) OnChange(listener func()) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// Getting label of address, nil is returned for unknown address.
+func (s *Store) Label(address string) *entity.Label {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.labels[strings.ToLower(address)]
+}
+
+// Getting count of labeled addresses.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.labels)
+}
+
+// CSV file contains columns address, name and category, category is optional.
+func labelFromRow(row []string) (*labelRecord, error) {
+	if len(row) < 2 || len(row) > 3 {
+		return nil, ErrInvalidLabel
+	}
+
+	res := &labelRecord{Address: row[0], Name: row[1]}
+	if len(row) == 3 {
+		res.Category = row[2]
+	}
+
+	return res, nil
+}
@@ -0,0 +1,133 @@
+package labels
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/go-playground/assert"
+)
+
+const (
+	testAddressA = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	testAddressB = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+// Writing labels file with name in temp directory.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func Test_Reload(t *testing.T) {
+	for _, test := range testsReload {
+		t.Run(test.name, func(t *testing.T) {
+			s := New(writeFile(t, test.fileName, test.content))
+
+			err := s.Reload()
+
+			assert.Equal(t, errors.Is(err, test.expectedError), true)
+			assert.Equal(t, test.expectedLabels, s.labels)
+		})
+	}
+}
+
+var testsReload = []struct {
+	name           string
+	fileName       string
+	content        string
+	expectedLabels map[string]*entity.Label
+	expectedError  error
+}{
+	{
+		name:     "YAML",
+		fileName: "labels.yaml",
+		content: `
+- address: "0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+  name: "Binance 14"
+  category: "Exchange"
+- address: "` + testAddressB + `"
+  name: "Arbitrum Bridge"
+`,
+		expectedLabels: map[string]*entity.Label{
+			testAddressA: {Name: "Binance 14", Category: "exchange"},
+			testAddressB: {Name: "Arbitrum Bridge"},
+		},
+		expectedError: nil,
+	},
+	{
+		name:     "CSV with header",
+		fileName: "labels.csv",
+		content: "address,name,category\n" +
+			"# builders\n" +
+			testAddressA + ",beaverbuild,builder\n" +
+			testAddressB + ",Unknown\n",
+		expectedLabels: map[string]*entity.Label{
+			testAddressA: {Name: "beaverbuild", Category: "builder"},
+			testAddressB: {Name: "Unknown"},
+		},
+		expectedError: nil,
+	},
+	{
+		name:           "Empty file",
+		fileName:       "labels.yml",
+		content:        "",
+		expectedLabels: map[string]*entity.Label{},
+		expectedError:  nil,
+	},
+	{
+		name:           "Invalid address",
+		fileName:       "labels.csv",
+		content:        "0x123,Binance 14,exchange\n",
+		expectedLabels: map[string]*entity.Label{},
+		expectedError:  ErrInvalidLabel,
+	},
+	{
+		name:           "Unknown format",
+		fileName:       "labels.json",
+		content:        "[]",
+		expectedLabels: map[string]*entity.Label{},
+		expectedError:  ErrUnknownFormat,
+	},
+}
+
+func Test_Reload_KeepsLabelsOnError(t *testing.T) {
+	path := writeFile(t, "labels.csv", testAddressA+",Binance 14,exchange\n")
+
+	s := New(path)
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	var changes int
+
+	s.OnChange(func() { changes++ })
+
+	if err := os.WriteFile(path, []byte("broken\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotEqual(t, nil, s.Reload())
+	assert.Equal(t, &entity.Label{Name: "Binance 14", Category: "exchange"}, s.Label(testAddressA))
+	assert.Equal(t, 1, s.Len())
+	// Listeners are called only if labels are replaced
+	assert.Equal(t, 0, changes)
+	assert.Equal(t, nil, os.WriteFile(path, []byte(testAddressA+",Binance 15,exchange\n"), 0o600))
+	assert.Equal(t, nil, s.Reload())
+	assert.Equal(t, 1, changes)
+}
+
+func Test_Label_WithoutPath(t *testing.T) {
+	s := New("")
+
+	assert.Equal(t, nil, s.Reload())
+	assert.Equal(t, (*entity.Label)(nil), s.Label(testAddressA))
+}
@@ -0,0 +1,74 @@
+// Package records implements reading of address records from local YAML or CSV file.
+package records
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnknownFormat = errors.New("unknown format of records file")
+
+// Reading records from file, format is chosen by extension.
+// YAML file contains list of records, rows of CSV file are converted to records by fromRow.
+func Read[T any](path string, fromRow func(row []string) (T, error)) ([]T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return readYAML[T](f)
+	case ".csv":
+		return readCSV(f, fromRow)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func readYAML[T any](r io.Reader) ([]T, error) {
+	var res []T
+
+	if err := yaml.NewDecoder(r).Decode(&res); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// First column of CSV file is address, header is optional and is skipped.
+func readCSV[T any](r io.Reader, fromRow func(row []string) (T, error)) ([]T, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]T, 0, len(rows))
+
+	for i, row := range rows {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(row[0]), "address") {
+			continue
+		}
+
+		record, err := fromRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+
+		res = append(res, record)
+	}
+
+	return res, nil
+}
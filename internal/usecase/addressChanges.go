@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Get changes of address in each block described by query and total change.
+// Blocks in which address isn't changed are skipped.
+func (uc *StatsOfChangingUseCase) GetAddressChanges(
+	ctx context.Context,
+	address string,
+	query entity.ChangesQuery,
+) (*entity.AddressChanges, error) {
+	addr, err := entity.NormalizeAddress(address)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - GetAddressChanges - entity.NormalizeAddress: %w", err)
+	}
+	// Getting range of blocks in which changes are searched.
+	br, err := uc.getBlockRange(ctx, query)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - GetAddressChanges - getBlockRange: %w", err)
+	}
+	// Getting changes of each block without merging them.
+	blocks, err := uc.getChangesByBlock(ctx, br, query)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - GetAddressChanges - getChangesByBlock: %w", err)
+	}
+
+	if err := uc.setBlockRangeTimestamps(ctx, br, query, blocks); err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - GetAddressChanges - setBlockRangeTimestamps: %w", err)
+	}
+
+	return getAddressHistory(addr, blocks, br), nil
+}
+
+// Getting series of changes of address from changes of blocks ordered by number.
+func getAddressHistory(addr string, blocks []*blockChanges, br *blockRange) *entity.AddressChanges {
+	res := &entity.AddressChanges{
+		Address:        addr,
+		Changes:        make([]*entity.BlockChange, 0),
+		FirstBlock:     int2hex(br.first),
+		LastBlock:      int2hex(br.last),
+		FirstBlockTime: br.firstTimestamp,
+		LastBlockTime:  br.lastTimestamp,
+		CountOfBlocks:  int64(br.count),
+		Anchor:         br.anchor,
+		AnchorBlock:    int2hex(br.anchorBlock),
+	}
+
+	total := new(big.Int)
+
+	for i, chs := range blocks {
+		res.Token = chs.token
+
+		change, ok := chs.addresses[addr]
+		if !ok {
+			continue
+		}
+
+		amount := change.net()
+		if amount.Sign() == 0 {
+			continue
+		}
+
+		total.Add(total, amount)
+
+		res.Changes = append(res.Changes, &entity.BlockChange{
+			Block:      int2hex(new(big.Int).Add(br.first, big.NewInt(int64(i)))),
+			Amount:     int2hex(new(big.Int).Abs(amount)),
+			IsRecieved: amount.Sign() > 0,
+		})
+	}
+	// Total will be unsigned like amounts of changes
+	res.Total = int2hex(new(big.Int).Abs(total))
+	res.IsRecieved = total.Sign() > 0
+
+	return res
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	mock "github.com/egor-denisov/biggest-change/internal/usecase/mocks"
+
+	"github.com/go-playground/assert"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	testAddressA = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	testAddressB = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	testAddressC = "0xcccccccccccccccccccccccccccccccccccccccc"
+)
+
+func Test_GetAddressChanges(t *testing.T) {
+	for _, test := range testsGetAddressChanges {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock.NewMockStatsOfChangingWebAPI(c)
+			test.mockBehavior(service, test.query.CountOfBlocks)
+
+			// Call function
+			changes, err := New(service).GetAddressChanges(context.Background(), test.address, test.query)
+
+			assert.Equal(t, test.expectedResult, changes)
+			assert.Equal(t, errors.Is(err, test.expectedError), true)
+		})
+	}
+}
+
+var testsGetAddressChanges = []struct {
+	name           string
+	mockBehavior   mockBehavior
+	address        string
+	query          entity.ChangesQuery
+	expectedResult *entity.AddressChanges
+	expectedError  error
+}{
+	{
+		name: "Success - Ether",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(198)).Return(&entity.Block{
+				Timestamp: 1000,
+				Transactions: []*entity.Transaction{
+					{From: testAddressA, To: testAddressB, Value: big.NewInt(100), Gas: big.NewInt(5), GasPrice: big.NewInt(2)},
+				},
+			}, nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(199)).Return(&entity.Block{Timestamp: 1012}, nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{
+				Timestamp: 1024,
+				Transactions: []*entity.Transaction{
+					{From: testAddressB, To: testAddressA, Value: big.NewInt(30), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+				},
+			}, nil)
+		},
+		address: strings.ToUpper(testAddressA),
+		query:   entity.ChangesQuery{CountOfBlocks: 3},
+		expectedResult: &entity.AddressChanges{
+			Address: testAddressA,
+			Changes: []*entity.BlockChange{
+				{Block: "0xc6", Amount: "0x6e", IsRecieved: false},
+				{Block: "0xc8", Amount: "0x1e", IsRecieved: true},
+			},
+			Total:          "0x50",
+			IsRecieved:     false,
+			FirstBlock:     "0xc6",
+			LastBlock:      "0xc8",
+			FirstBlockTime: 1000,
+			LastBlockTime:  1024,
+			CountOfBlocks:  3,
+			Anchor:         "latest",
+			AnchorBlock:    "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Token",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetTokenDecimals(gomock.Any(), testToken).Return(uint8(6), nil)
+			m.EXPECT().GetTokenTransfers(gomock.Any(), testToken, big.NewInt(199), big.NewInt(200)).
+				Return([]*entity.TokenTransfer{
+					{Token: testToken, From: testAddressB, To: testAddressA, Value: big.NewInt(700), BlockNumber: big.NewInt(199)},
+					{Token: testToken, From: testAddressA, To: testAddressB, Value: big.NewInt(200), BlockNumber: big.NewInt(199)},
+				}, nil)
+			m.EXPECT().GetBlockHeader(gomock.Any(), big.NewInt(199)).Return(&entity.BlockHeader{Timestamp: 1012}, nil)
+			m.EXPECT().GetBlockHeader(gomock.Any(), big.NewInt(200)).Return(&entity.BlockHeader{Timestamp: 1024}, nil)
+		},
+		address: testAddressA,
+		query:   entity.ChangesQuery{CountOfBlocks: 2, Token: testToken},
+		expectedResult: &entity.AddressChanges{
+			Address:        testAddressA,
+			Changes:        []*entity.BlockChange{{Block: "0xc7", Amount: "0x1f4", IsRecieved: true}},
+			Total:          "0x1f4",
+			IsRecieved:     true,
+			FirstBlock:     "0xc7",
+			LastBlock:      "0xc8",
+			FirstBlockTime: 1012,
+			LastBlockTime:  1024,
+			CountOfBlocks:  2,
+			Anchor:         "latest",
+			AnchorBlock:    "0xc8",
+			Token:          &entity.Token{Address: testToken, Decimals: 6},
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Without Changes",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{Timestamp: 1024}, nil)
+		},
+		address: testAddressA,
+		query:   entity.ChangesQuery{CountOfBlocks: 1},
+		expectedResult: &entity.AddressChanges{
+			Address:        testAddressA,
+			Changes:        []*entity.BlockChange{},
+			Total:          "0x0",
+			FirstBlock:     "0xc8",
+			LastBlock:      "0xc8",
+			FirstBlockTime: 1024,
+			LastBlockTime:  1024,
+			CountOfBlocks:  1,
+			Anchor:         "latest",
+			AnchorBlock:    "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name:           "Error - Invalid Address",
+		mockBehavior:   func(_ *mock.MockStatsOfChangingWebAPI, _ uint) {},
+		address:        "0x123",
+		query:          entity.ChangesQuery{CountOfBlocks: 1},
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidAddress,
+	},
+	{
+		name: "Error - Getting block",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(nil, errSomethingWentWrong)
+		},
+		address:        testAddressA,
+		query:          entity.ChangesQuery{CountOfBlocks: 1},
+		expectedResult: nil,
+		expectedError:  errSomethingWentWrong,
+	},
+}
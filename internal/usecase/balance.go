@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Balances of address before window and at the end of window.
+type balance struct {
+	start *big.Int
+	end   *big.Int
+}
+
+// Getting change of balance in percent, nil is returned if balance before window is zero.
+func (b *balance) percentChange() *float64 {
+	if b.start.Sign() == 0 {
+		return nil
+	}
+
+	diff := new(big.Float).SetInt(new(big.Int).Sub(b.end, b.start))
+	res, _ := diff.Quo(diff, new(big.Float).SetInt(b.start)).Float64()
+	res *= 100
+
+	return &res
+}
+
+// Loading balances of found addresses or groups which aren't loaded yet.
+// Balance of group is sum of balances of its addresses.
+func (uc *StatsOfChangingUseCase) loadBalances(
+	ctx context.Context,
+	keys []string,
+	br *blockRange,
+	token *entity.Token,
+	rk *ranking,
+) error {
+	if rk.balances == nil {
+		rk.balances = make(map[string]*balance, len(keys))
+	}
+
+	var addrs []string
+
+	for _, key := range keys {
+		if _, ok := rk.balances[key]; ok {
+			continue
+		}
+
+		addrs = append(addrs, rk.addresses(key)...)
+	}
+
+	balances, err := uc.getBalances(ctx, addrs, br, token)
+	if err != nil {
+		return fmt.Errorf("StatsOfChangingUseCase - loadBalances - getBalances: %w", err)
+	}
+
+	for _, key := range keys {
+		if _, ok := rk.balances[key]; ok {
+			continue
+		}
+
+		res := &balance{start: new(big.Int), end: new(big.Int)}
+		for _, addr := range rk.addresses(key) {
+			res.start.Add(res.start, balances[addr].start)
+			res.end.Add(res.end, balances[addr].end)
+		}
+
+		rk.balances[key] = res
+	}
+
+	return nil
+}
+
+// Getting balances of addresses concurrently, count of goroutines is limited like for blocks.
+func (uc *StatsOfChangingUseCase) getBalances(
+	ctx context.Context,
+	addrs []string,
+	br *blockRange,
+	token *entity.Token,
+) (map[string]*balance, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	res := make(map[string]*balance, len(addrs))
+	pool := make(chan struct{}, uc.maxGoroutines)
+
+	for _, addr := range addrs {
+		pool <- struct{}{}
+
+		wg.Add(1)
+
+		go func(addr string) {
+			defer wg.Done()
+			defer func() { <-pool }()
+
+			b, err := uc.getBalance(ctx, addr, br, token)
+
+			mu.Lock()
+			defer mu.Unlock()
+			// Other requests are cancelled after first error
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+
+					cancel()
+				}
+
+				return
+			}
+
+			res[addr] = b
+		}(addr)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return res, nil
+}
+
+// Getting balance of address at block before window and at last block of window.
+// Window which starts from genesis has zero balance before it.
+func (uc *StatsOfChangingUseCase) getBalance(
+	ctx context.Context,
+	addr string,
+	br *blockRange,
+	token *entity.Token,
+) (*balance, error) {
+	res := &balance{start: new(big.Int)}
+
+	if br.first.Sign() > 0 {
+		start, err := uc.getBalanceAt(ctx, addr, new(big.Int).Sub(br.first, big.NewInt(1)), token)
+		if err != nil {
+			return nil, err
+		}
+
+		res.start = start
+	}
+
+	end, err := uc.getBalanceAt(ctx, addr, br.last, token)
+	if err != nil {
+		return nil, err
+	}
+
+	res.end = end
+
+	return res, nil
+}
+
+// Getting balance of address in ether or in token at the end of block.
+func (uc *StatsOfChangingUseCase) getBalanceAt(
+	ctx context.Context,
+	addr string,
+	blockNumber *big.Int,
+	token *entity.Token,
+) (*big.Int, error) {
+	if token != nil {
+		res, err := uc.webAPI.GetTokenBalance(ctx, token.Address, addr, blockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("StatsOfChangingUseCase - getBalanceAt - uc.webAPI.GetTokenBalance: %w", err)
+		}
+
+		return res, nil
+	}
+
+	res, err := uc.webAPI.GetBalance(ctx, addr, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - getBalanceAt - uc.webAPI.GetBalance: %w", err)
+	}
+
+	return res, nil
+}
+
+// Setting balances of found address.
+func (uc *StatsOfChangingUseCase) setBalances(
+	ctx context.Context,
+	res *entity.BiggestChange,
+	br *blockRange,
+	token *entity.Token,
+	rk *ranking,
+) error {
+	if res.Address == "" {
+		return nil
+	}
+
+	if err := uc.loadBalances(ctx, []string{res.Address}, br, token, rk); err != nil {
+		return fmt.Errorf("StatsOfChangingUseCase - setBalances - loadBalances: %w", err)
+	}
+
+	res.StartBalance, res.EndBalance, res.PercentChange = rk.balance(res.Address)
+
+	return nil
+}
+
+// Setting balances of addresses in ranking.
+func (uc *StatsOfChangingUseCase) setTopBalances(
+	ctx context.Context,
+	res *entity.TopChanges,
+	br *blockRange,
+	token *entity.Token,
+	rk *ranking,
+) error {
+	keys := make([]string, 0, len(res.Changes))
+	for _, change := range res.Changes {
+		keys = append(keys, change.Address)
+	}
+
+	if err := uc.loadBalances(ctx, keys, br, token, rk); err != nil {
+		return fmt.Errorf("StatsOfChangingUseCase - setTopBalances - loadBalances: %w", err)
+	}
+
+	for _, change := range res.Changes {
+		change.StartBalance, change.EndBalance, change.PercentChange = rk.balance(change.Address)
+	}
+
+	return nil
+}
+
+// Getting loaded balances of address or group in format of response.
+func (r *ranking) balance(key string) (string, string, *float64) {
+	b, ok := r.balances[key]
+	if !ok {
+		return "", "", nil
+	}
+
+	return int2hex(b.start), int2hex(b.end), b.percentChange()
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Get last block which is mined at or before time t.
+func (uc *StatsOfChangingUseCase) GetBlockAtTime(ctx context.Context, t time.Time) (*entity.BlockAtTime, error) {
+	if t.Unix() < 0 {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - GetBlockAtTime - %s: %w", t, entity.ErrTimeBeforeGenesis)
+	}
+	// Blocks after anchor aren't confirmed yet, so searching before it
+	anchorBlock, err := uc.getAnchorBlock(ctx, uc.anchor)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - GetBlockAtTime - getAnchorBlock: %w", err)
+	}
+
+	anchorTime, err := uc.getHeaderTimestamp(ctx, anchorBlock.Int64())
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - GetBlockAtTime - getHeaderTimestamp: %w", err)
+	}
+	// Searched block is previous to first block mined after t
+	next, err := uc.searchBlockByTime(ctx, anchorBlock.Int64(), anchorTime, uint64(t.Unix())+1, uc.getHeaderTimestamp)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - GetBlockAtTime - searchBlockByTime: %w", err)
+	}
+
+	if next == 0 {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - GetBlockAtTime - %s: %w", t, entity.ErrTimeBeforeGenesis)
+	}
+
+	header, err := uc.getBlockHeader(ctx, next-1)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - GetBlockAtTime - getBlockHeader: %w", err)
+	}
+
+	return &entity.BlockAtTime{
+		Number:    int2hex(header.Number),
+		Hash:      header.Hash,
+		Timestamp: header.Timestamp,
+	}, nil
+}
+
+// Getting header of block by number.
+// Headers are cached separately from blocks, because they are much smaller and are got more often.
+func (uc *StatsOfChangingUseCase) getBlockHeader(ctx context.Context, blockNumber int64) (*entity.BlockHeader, error) {
+	key := big.NewInt(blockNumber).String()
+
+	if cachedResult, ok := uc.headerCache.Get(key); ok {
+		res, ok := cachedResult.(*entity.BlockHeader)
+		if ok {
+			return res, nil
+		}
+	}
+	// Request to web api waits for limiter, so cache also saves its budget
+	header, err := uc.webAPI.GetBlockHeader(ctx, big.NewInt(blockNumber))
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - getBlockHeader - uc.webAPI.GetBlockHeader: %w", err)
+	}
+
+	uc.headerCache.Add(key, header)
+
+	return header, nil
+}
+
+// Getting timestamp of block through cache of headers.
+func (uc *StatsOfChangingUseCase) getHeaderTimestamp(ctx context.Context, blockNumber int64) (uint64, error) {
+	header, err := uc.getBlockHeader(ctx, blockNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	return header.Timestamp, nil
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	mock "github.com/egor-denisov/biggest-change/internal/usecase/mocks"
+
+	"github.com/go-playground/assert"
+	"github.com/golang/mock/gomock"
+)
+
+func Test_GetBlockAtTime(t *testing.T) {
+	for _, test := range testsGetBlockAtTime {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock.NewMockStatsOfChangingWebAPI(c)
+			test.mockBehavior(service)
+
+			// Call function
+			block, err := New(service).GetBlockAtTime(context.Background(), test.time)
+
+			assert.Equal(t, test.expectedResult, block)
+			assert.Equal(t, errors.Is(err, test.expectedError), true)
+		})
+	}
+}
+
+// Mocking chain with head 10, where block n is mined at 100 + 12*n.
+// Every header can be requested only once, the others are got from cache.
+func mockTimedHeaders(m *mock.MockStatsOfChangingWebAPI) {
+	m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(10), nil)
+
+	for n := int64(0); n <= 10; n++ {
+		m.EXPECT().GetBlockHeader(gomock.Any(), big.NewInt(n)).Return(&entity.BlockHeader{
+			Number:    big.NewInt(n),
+			Hash:      fmt.Sprintf("0xh%d", n),
+			Timestamp: uint64(100 + 12*n),
+		}, nil).MaxTimes(1)
+	}
+}
+
+var testsGetBlockAtTime = []struct {
+	name           string
+	mockBehavior   func(m *mock.MockStatsOfChangingWebAPI)
+	time           time.Time
+	expectedResult *entity.BlockAtTime
+	expectedError  error
+}{
+	{
+		name:           "Success - Between Blocks",
+		mockBehavior:   mockTimedHeaders,
+		time:           time.Unix(200, 0),
+		expectedResult: &entity.BlockAtTime{Number: "0x8", Hash: "0xh8", Timestamp: 196},
+		expectedError:  nil,
+	},
+	{
+		name:           "Success - Exactly At Block",
+		mockBehavior:   mockTimedHeaders,
+		time:           time.Unix(160, 0),
+		expectedResult: &entity.BlockAtTime{Number: "0x5", Hash: "0xh5", Timestamp: 160},
+		expectedError:  nil,
+	},
+	{
+		name:           "Success - After Head",
+		mockBehavior:   mockTimedHeaders,
+		time:           time.Unix(1000, 0),
+		expectedResult: &entity.BlockAtTime{Number: "0xa", Hash: "0xh10", Timestamp: 220},
+		expectedError:  nil,
+	},
+	{
+		name:           "Success - Genesis",
+		mockBehavior:   mockTimedHeaders,
+		time:           time.Unix(105, 0),
+		expectedResult: &entity.BlockAtTime{Number: "0x0", Hash: "0xh0", Timestamp: 100},
+		expectedError:  nil,
+	},
+	{
+		name:           "Error - Before Genesis",
+		mockBehavior:   mockTimedHeaders,
+		time:           time.Unix(50, 0),
+		expectedResult: nil,
+		expectedError:  entity.ErrTimeBeforeGenesis,
+	},
+	{
+		name: "Error - Getting header",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(10), nil)
+			m.EXPECT().GetBlockHeader(gomock.Any(), big.NewInt(10)).Return(nil, errSomethingWentWrong)
+		},
+		time:           time.Unix(200, 0),
+		expectedResult: nil,
+		expectedError:  errSomethingWentWrong,
+	},
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"math/big"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Changes of addresses balances in block or in range of blocks.
+type blockChanges struct {
+	hash               string
+	parentHash         string
+	timestamp          uint64
+	addresses          map[string]*addressChange
+	failedTransactions int64
+	burnedFees         *big.Int
+	createdContracts   map[string]struct{}
+	token              *entity.Token
+}
+
+// Flows of address balance, net change is calculated from them.
+// Values are never changed in place, because changes of blocks are shared through cache.
+type addressChange struct {
+	received *big.Int
+	sent     *big.Int
+	fees     *big.Int
+	txCount  int64
+	txs      []*txRef
+}
+
+func newBlockChanges(size int) *blockChanges {
+	return &blockChanges{
+		addresses:        make(map[string]*addressChange, size),
+		burnedFees:       new(big.Int),
+		createdContracts: make(map[string]struct{}),
+	}
+}
+
+// Getting change of address, which is created if address isn't changed yet.
+func (bc *blockChanges) change(addr string) *addressChange {
+	if bc.addresses[addr] == nil {
+		bc.addresses[addr] = &addressChange{
+			received: new(big.Int),
+			sent:     new(big.Int),
+			fees:     new(big.Int),
+		}
+	}
+
+	return bc.addresses[addr]
+}
+
+// Adding amount which address got.
+func (bc *blockChanges) addReceived(addr string, amount *big.Int) {
+	c := bc.change(addr)
+	c.received = new(big.Int).Add(c.received, amount)
+}
+
+// Adding amount which address sent.
+func (bc *blockChanges) addSent(addr string, amount *big.Int) {
+	c := bc.change(addr)
+	c.sent = new(big.Int).Add(c.sent, amount)
+}
+
+// Adding fee which address paid.
+func (bc *blockChanges) addFee(addr string, fee *big.Int) {
+	c := bc.change(addr)
+	c.fees = new(big.Int).Add(c.fees, fee)
+}
+
+// Counting transaction in which address took part.
+func (bc *blockChanges) addTransaction(addr string) {
+	bc.change(addr).txCount++
+}
+
+// Adding transfer of amount from one address to another.
+func (bc *blockChanges) addTransfer(from, to string, amount *big.Int) {
+	bc.addSent(from, amount)
+	bc.addReceived(to, amount)
+}
+
+// Getting copy of address change, zero change is returned if address isn't changed yet.
+func (bc *blockChanges) copyChange(addr string) *addressChange {
+	if c := bc.addresses[addr]; c != nil {
+		res := *c
+
+		return &res
+	}
+
+	return &addressChange{
+		received: new(big.Int),
+		sent:     new(big.Int),
+		fees:     new(big.Int),
+	}
+}
+
+// Adding flows and transactions of change to address.
+// Change of address is replaced instead of changing it in place, so snapshots of bc aren't affected.
+func (bc *blockChanges) addChange(addr string, change *addressChange) {
+	c := bc.copyChange(addr)
+	c.received = new(big.Int).Add(c.received, change.received)
+	c.sent = new(big.Int).Add(c.sent, change.sent)
+	c.fees = new(big.Int).Add(c.fees, change.fees)
+	c.txCount += change.txCount
+
+	for _, ref := range change.txs {
+		c.txs = appendTxRef(c.txs, ref)
+	}
+
+	bc.addresses[addr] = c
+}
+
+// Merging changes of other block into bc.
+func (bc *blockChanges) merge(other *blockChanges) {
+	for addr, change := range other.addresses {
+		bc.addChange(addr, change)
+	}
+
+	bc.failedTransactions += other.failedTransactions
+	bc.burnedFees = new(big.Int).Add(bc.burnedFees, other.burnedFees)
+
+	for addr := range other.createdContracts {
+		bc.createdContracts[addr] = struct{}{}
+	}
+
+	if other.token != nil {
+		bc.token = other.token
+	}
+}
+
+// Subtracting changes of other block with number from bc, addresses without changes are removed.
+// Transactions of block are removed too, but transactions which were cut off before aren't restored.
+func (bc *blockChanges) subtract(other *blockChanges, blockNumber uint64) {
+	for addr, change := range other.addresses {
+		c := bc.copyChange(addr)
+		c.received = new(big.Int).Sub(c.received, change.received)
+		c.sent = new(big.Int).Sub(c.sent, change.sent)
+		c.fees = new(big.Int).Sub(c.fees, change.fees)
+		c.txCount -= change.txCount
+		c.txs = removeTxRefs(c.txs, blockNumber)
+
+		if c.received.Sign() == 0 && c.sent.Sign() == 0 && c.fees.Sign() == 0 && c.txCount == 0 {
+			delete(bc.addresses, addr)
+		} else {
+			bc.addresses[addr] = c
+		}
+	}
+
+	bc.failedTransactions -= other.failedTransactions
+	bc.burnedFees = new(big.Int).Sub(bc.burnedFees, other.burnedFees)
+
+	for addr := range other.createdContracts {
+		delete(bc.createdContracts, addr)
+	}
+}
+
+// Getting snapshot of changes which isn't affected by next merges and subtractions of bc.
+// Merge and subtract replace changes of addresses instead of changing them in place,
+// so snapshot shares changes with bc and only maps are copied.
+func (bc *blockChanges) snapshot() *blockChanges {
+	res := newBlockChanges(len(bc.addresses))
+	res.hash = bc.hash
+	res.parentHash = bc.parentHash
+	res.timestamp = bc.timestamp
+	res.failedTransactions = bc.failedTransactions
+	res.burnedFees = bc.burnedFees
+	res.token = bc.token
+
+	for addr, change := range bc.addresses {
+		res.addresses[addr] = change
+	}
+
+	for addr := range bc.createdContracts {
+		res.createdContracts[addr] = struct{}{}
+	}
+
+	return res
+}
+
+// Checking that bc is child of parent block.
+// Blocks without known hashes can't be checked, so they are considered linked.
+func (bc *blockChanges) isChildOf(parent *blockChanges) bool {
+	if bc.parentHash == "" || parent.hash == "" {
+		return true
+	}
+
+	return bc.parentHash == parent.hash
+}
+
+// Checking that address is contract created in block or in range of blocks.
+func (bc *blockChanges) isCreatedContract(addr string) bool {
+	_, ok := bc.createdContracts[addr]
+
+	return ok
+}
+
+// Getting net change of address balance.
+func (c *addressChange) net() *big.Int {
+	res := new(big.Int).Sub(c.received, c.sent)
+
+	return res.Sub(res, c.fees)
+}
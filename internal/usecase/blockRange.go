@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Range of blocks in which changes of addresses are calculated.
+type blockRange struct {
+	first          *big.Int
+	last           *big.Int
+	count          int
+	anchor         string
+	anchorBlock    *big.Int
+	firstTimestamp uint64
+	lastTimestamp  uint64
+}
+
+// Resolving block range described by query.
+// Without bounds the range ends on the anchor block.
+func (uc *StatsOfChangingUseCase) getBlockRange(
+	ctx context.Context,
+	query entity.ChangesQuery,
+) (*blockRange, error) {
+	countOfBlocks := query.CountOfBlocks
+	if countOfBlocks == 0 {
+		countOfBlocks = uc.countOfBlocks
+	}
+	anchor := query.Anchor
+	if anchor == "" {
+		anchor = uc.anchor
+	}
+	// Getting number of anchor block, bounds can't be after it.
+	anchorBlock, err := uc.getAnchorBlock(ctx, anchor)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - getBlockRange - getAnchorBlock: %w", err)
+	}
+
+	var first, last *big.Int
+
+	switch {
+	case query.Since != nil || query.Duration != 0:
+		first, last, err = uc.getTimeBlockRange(ctx, query, anchorBlock)
+		if err != nil {
+			return nil,
+				fmt.Errorf("StatsOfChangingUseCase - getBlockRange - getTimeBlockRange: %w", err)
+		}
+	case query.FromBlock != nil && query.ToBlock != nil:
+		first = new(big.Int).SetUint64(*query.FromBlock)
+		last = new(big.Int).SetUint64(*query.ToBlock)
+	case query.FromBlock != nil:
+		first = new(big.Int).SetUint64(*query.FromBlock)
+		last = new(big.Int).Add(first, big.NewInt(int64(countOfBlocks-1)))
+	case query.ToBlock != nil:
+		last = new(big.Int).SetUint64(*query.ToBlock)
+		first = new(big.Int).Sub(last, big.NewInt(int64(countOfBlocks-1)))
+	default:
+		last = anchorBlock
+		first = new(big.Int).Sub(last, big.NewInt(int64(countOfBlocks-1)))
+	}
+
+	if first.Sign() < 0 || first.Cmp(last) > 0 || last.Cmp(anchorBlock) > 0 {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - getBlockRange - [%s; %s] with %s block %s: %w",
+			first, last, anchor, anchorBlock, entity.ErrInvalidBlockRange)
+	}
+	// Every block of range is requested, so long ranges are rejected before it
+	count := new(big.Int).Sub(last, first)
+	if count.Add(count, big.NewInt(1)).Cmp(new(big.Int).SetUint64(uint64(uc.maxCountOfBlocks))) > 0 {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - getBlockRange - [%s; %s] is longer than %d blocks: %w",
+			first, last, uc.maxCountOfBlocks, entity.ErrInvalidBlockRange)
+	}
+
+	return &blockRange{
+		first:       first,
+		last:        last,
+		count:       int(count.Int64()),
+		anchor:      anchor,
+		anchorBlock: anchorBlock,
+	}, nil
+}
+
+// Getting number of block on which window is anchored.
+// Confirmation depth is subtracted only from the latest block,
+// because safe and finalized blocks are confirmed already.
+func (uc *StatsOfChangingUseCase) getAnchorBlock(ctx context.Context, anchor string) (*big.Int, error) {
+	switch anchor {
+	case entity.AnchorLatest:
+		currentBlock, err := uc.webAPI.GetCurrentBlockNumber(ctx)
+		if err != nil {
+			return nil,
+				fmt.Errorf("StatsOfChangingUseCase - getAnchorBlock - uc.webAPI.GetCurrentBlockNumber: %w", err)
+		}
+
+		return new(big.Int).Sub(currentBlock, new(big.Int).SetUint64(uint64(uc.confirmationDepth))), nil
+	case entity.AnchorSafe, entity.AnchorFinalized:
+		res, err := uc.webAPI.GetBlockNumberByTag(ctx, anchor)
+		if err != nil {
+			return nil,
+				fmt.Errorf("StatsOfChangingUseCase - getAnchorBlock - uc.webAPI.GetBlockNumberByTag: %w", err)
+		}
+
+		return res, nil
+	default:
+		return nil, fmt.Errorf("StatsOfChangingUseCase - getAnchorBlock - %q: %w", anchor, entity.ErrInvalidAnchor)
+	}
+}
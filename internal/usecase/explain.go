@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Count of transactions which are kept for each address.
+const _maxTxRefs = 10
+
+// Reference to transaction which changed balance of address.
+type txRef struct {
+	hash       string
+	block      uint64
+	value      *big.Int
+	fee        *big.Int
+	isRecieved bool
+}
+
+// Getting amount by which transaction changed balance of address.
+func (r *txRef) amount() *big.Int {
+	return new(big.Int).Add(r.value, r.fee)
+}
+
+// Keeping reference to transaction, only _maxTxRefs biggest ones are kept for address.
+func (bc *blockChanges) addTxRef(addr string, ref *txRef) {
+	c := bc.change(addr)
+	c.txs = appendTxRef(c.txs, ref)
+}
+
+// Appending reference to copy of refs, so refs shared through cache aren't changed.
+func appendTxRef(refs []*txRef, ref *txRef) []*txRef {
+	res := make([]*txRef, 0, len(refs)+1)
+	res = append(res, refs...)
+	res = append(res, ref)
+	// Biggest transactions go first, transactions with same amount are ordered by hash
+	sort.Slice(res, func(i, j int) bool {
+		if cmp := res[i].amount().Cmp(res[j].amount()); cmp != 0 {
+			return cmp > 0
+		}
+
+		return res[i].hash < res[j].hash
+	})
+
+	if len(res) > _maxTxRefs {
+		res = res[:_maxTxRefs]
+	}
+
+	return res
+}
+
+// Removing references to transactions of block, refs are copied like in appendTxRef.
+func removeTxRefs(refs []*txRef, blockNumber uint64) []*txRef {
+	res := make([]*txRef, 0, len(refs))
+
+	for _, ref := range refs {
+		if ref.block != blockNumber {
+			res = append(res, ref)
+		}
+	}
+
+	return res
+}
+
+// Getting transactions which made change of address by metric.
+// Inflow is made only by received transactions and outflow only by sent ones.
+func explainChange(c *addressChange, metric string) []*entity.ContributingTransaction {
+	res := make([]*entity.ContributingTransaction, 0, len(c.txs))
+
+	for _, r := range c.txs {
+		if (metric == entity.MetricInflow && !r.isRecieved) || (metric == entity.MetricOutflow && r.isRecieved) {
+			continue
+		}
+
+		res = append(res, &entity.ContributingTransaction{
+			Hash:       r.hash,
+			Block:      int2hex(new(big.Int).SetUint64(r.block)),
+			Value:      int2hex(r.value),
+			Fee:        int2hex(r.fee),
+			IsRecieved: r.isRecieved,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	sl "github.com/egor-denisov/biggest-change/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var followedBlock = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "biggest_change_followed_block",
+	Help: "Number of last block in window followed in background.",
+})
+
+// Listener of follower, it's called with number of new head after window is moved to it.
+// Listener is called in goroutine of follower, so it mustn't block.
+type BlockListener func(blockNumber *big.Int)
+
+// Window of default size with changes of its blocks, it's never changed after publishing.
+type followedWindow struct {
+	br      *blockRange
+	changes *blockChanges
+}
+
+// Follower keeps changes of default window up to date in background.
+// Each new block is fetched once: its changes are added to window
+// and changes of block which falls out of window are subtracted.
+type Follower struct {
+	uc        *StatsOfChangingUseCase
+	l         *slog.Logger
+	interval  time.Duration
+	first     *big.Int
+	blocks    []*blockChanges // Blocks of window ordered by number
+	total     *blockChanges
+	listeners []BlockListener
+	refresh   chan struct{}
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// Follower refreshes precomputed result of use case, so stale result can request refresh from it.
+func NewFollower(uc *StatsOfChangingUseCase, l *slog.Logger, interval time.Duration) *Follower {
+	f := &Follower{
+		uc:       uc,
+		l:        l,
+		interval: interval,
+		refresh:  make(chan struct{}, 1),
+	}
+
+	uc.revalidate = f.Refresh
+	// Default age of stale result is derived from interval
+	if uc.staleAfter == 0 {
+		uc.staleAfter = _staleFollowSteps * interval
+	}
+
+	return f
+}
+
+// Requesting step without waiting for ticker, requests made during step are joined.
+func (f *Follower) Refresh() {
+	select {
+	case f.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// Adding listener of new heads, listeners must be added before start.
+func (f *Follower) OnBlock(listener BlockListener) {
+	f.listeners = append(f.listeners, listener)
+}
+
+// Starting polling of head, follower without interval is disabled.
+func (f *Follower) Start() {
+	if f.interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	f.done = make(chan struct{})
+
+	go f.run(ctx)
+}
+
+// Stopping polling and waiting for current step.
+func (f *Follower) Stop() {
+	if f.cancel == nil {
+		return
+	}
+
+	f.cancel()
+	<-f.done
+}
+
+func (f *Follower) run(ctx context.Context) {
+	defer close(f.done)
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.step(ctx); err != nil && ctx.Err() == nil {
+			f.l.Error("usecase - Follower - step", sl.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-f.refresh:
+		}
+	}
+}
+
+// Moving window to current anchor block and publishing it.
+// Window is fetched again if it's the first step, blocks are skipped or chain is reorganized.
+func (f *Follower) step(ctx context.Context) error {
+	br, err := f.uc.getBlockRange(ctx, entity.ChangesQuery{})
+	if err != nil {
+		return fmt.Errorf("Follower - step - getBlockRange: %w", err)
+	}
+
+	if f.total != nil && f.last().Cmp(br.last) == 0 {
+		// Result is dropped when labels or clusters are changed, so it's computed again for the same head
+		if !f.uc.confirmHotResult() {
+			if err := f.uc.precompute(ctx); err != nil {
+				return fmt.Errorf("Follower - step - precompute: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	if f.total == nil || f.last().Cmp(br.first) < 0 || f.last().Cmp(br.last) > 0 {
+		err = f.reset(ctx, br)
+	} else {
+		err = f.advance(ctx, br)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	br.firstTimestamp = f.blocks[0].timestamp
+	br.lastTimestamp = f.blocks[len(f.blocks)-1].timestamp
+	// Total is changed by next steps, so its snapshot is published
+	f.uc.window.Store(&followedWindow{br: br, changes: f.total.snapshot()})
+	followedBlock.Set(float64(br.last.Int64()))
+	// Result for default query is computed once for every new head
+	if err := f.uc.precompute(ctx); err != nil {
+		return fmt.Errorf("Follower - step - precompute: %w", err)
+	}
+
+	for _, listener := range f.listeners {
+		listener(br.last)
+	}
+
+	return nil
+}
+
+// Fetching all blocks of window, cached blocks aren't requested again.
+func (f *Follower) reset(ctx context.Context, br *blockRange) error {
+	blocks, err := f.uc.getAddressChangesByBlock(ctx, br)
+	if err != nil {
+		return fmt.Errorf("Follower - reset - getAddressChangesByBlock: %w", err)
+	}
+
+	total := newBlockChanges(f.uc.averageAddressCountInBlock * br.count)
+	for _, chs := range blocks {
+		total.merge(chs)
+	}
+
+	f.first = br.first
+	f.blocks = blocks
+	f.total = total
+
+	return nil
+}
+
+// Adding new blocks to window and subtracting blocks which fall out of it.
+// If new block isn't child of the last one, window is fetched again.
+func (f *Follower) advance(ctx context.Context, br *blockRange) error {
+	for n := new(big.Int).Add(f.last(), big.NewInt(1)); n.Cmp(br.last) <= 0; n.Add(n, big.NewInt(1)) {
+		chs, err := f.uc.getAddressWithChanges(ctx, n)
+		if err != nil {
+			return fmt.Errorf("Follower - advance - getAddressWithChanges: %w", err)
+		}
+
+		if !chs.isChildOf(f.blocks[len(f.blocks)-1]) {
+			reorgsTotal.Inc()
+
+			return f.reset(ctx, br)
+		}
+
+		f.blocks = append(f.blocks, chs)
+		f.total.merge(chs)
+	}
+
+	for f.first.Cmp(br.first) < 0 {
+		f.total.subtract(f.blocks[0], f.first.Uint64())
+		f.blocks = f.blocks[1:]
+		f.first = new(big.Int).Add(f.first, big.NewInt(1))
+	}
+
+	return nil
+}
+
+// Getting number of last block in window.
+func (f *Follower) last() *big.Int {
+	return new(big.Int).Add(f.first, big.NewInt(int64(len(f.blocks)-1)))
+}
+
+// Getting window followed in background if query describes it, otherwise nil is returned.
+// Explained transactions are cut off in window, so they are got from blocks.
+func (uc *StatsOfChangingUseCase) getFollowedWindow(query entity.ChangesQuery) *followedWindow {
+	if query.Token != "" || query.FromBlock != nil || query.ToBlock != nil || query.Since != nil ||
+		query.Duration != 0 || query.Explain {
+		return nil
+	}
+
+	if (query.CountOfBlocks != 0 && query.CountOfBlocks != uc.countOfBlocks) ||
+		(query.Anchor != "" && query.Anchor != uc.anchor) {
+		return nil
+	}
+
+	return uc.window.Load()
+}
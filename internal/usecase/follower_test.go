@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	mock "github.com/egor-denisov/biggest-change/internal/usecase/mocks"
+	"github.com/egor-denisov/biggest-change/pkg/logger"
+
+	"github.com/go-playground/assert"
+	"github.com/golang/mock/gomock"
+)
+
+// Getting block with hashes and single transfer.
+func linkedBlock(hash, parentHash, from, to string, value int64) *entity.Block {
+	return &entity.Block{
+		Hash:       hash,
+		ParentHash: parentHash,
+		Timestamp:  1000,
+		Transactions: []*entity.Transaction{
+			{From: from, To: to, Value: big.NewInt(value), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+		},
+	}
+}
+
+func Test_Follower(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	m := mock.NewMockStatsOfChangingWebAPI(c)
+	uc := New(m, CountOfBlocks(2), StaleAfter(time.Hour))
+	f := NewFollower(uc, logger.SetupLogger("debug"), 0)
+	ctx := context.Background()
+
+	var heads []*big.Int
+
+	f.OnBlock(func(blockNumber *big.Int) { heads = append(heads, blockNumber) })
+
+	// Window [199; 200] is fetched on the first step
+	gomock.InOrder(
+		m.EXPECT().GetCurrentBlockNumber(ctx).Return(big.NewInt(200), nil),
+		m.EXPECT().GetCurrentBlockNumber(ctx).Return(big.NewInt(200), nil),
+		m.EXPECT().GetCurrentBlockNumber(ctx).Return(big.NewInt(201), nil),
+	)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(199)).
+		Return(linkedBlock("0xh199", "0xh198", "0x1", "0x2", 100), nil)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).
+		Return(linkedBlock("0xh200", "0xh199", "0x2", "0x3", 50), nil)
+
+	assert.Equal(t, nil, f.step(ctx))
+
+	res, err := uc.GetAddressWithBiggestChange(ctx, entity.ChangesQuery{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "0x1", res.Address)
+	assert.Equal(t, "0xc7", res.FirstBlock)
+
+	// Head isn't changed, so nothing is fetched
+	assert.Equal(t, nil, f.step(ctx))
+
+	// Only new block is fetched, block 199 falls out of window
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(201)).
+		Return(linkedBlock("0xh201", "0xh200", "0x3", "0x1", 10), nil)
+
+	assert.Equal(t, nil, f.step(ctx))
+
+	res, err = uc.GetAddressWithBiggestChange(ctx, entity.ChangesQuery{CountOfBlocks: 2})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, &entity.BiggestChange{
+		Address:        "0x2",
+		Amount:         "0x32",
+		FirstBlock:     "0xc8",
+		LastBlock:      "0xc9",
+		FirstBlockTime: 1000,
+		LastBlockTime:  1000,
+		Metric:         entity.MetricNet,
+		CountOfBlocks:  2,
+		BurnedFees:     "0x0",
+		Anchor:         "latest",
+		AnchorBlock:    "0xc9",
+		ComputedAt:     res.ComputedAt,
+	}, res)
+	assert.NotEqual(t, nil, res.ComputedAt)
+	// Address 0x1 is left only with transfer of block 201
+	assert.Equal(t, "10", f.total.addresses["0x1"].net().String())
+	// Listeners aren't called if head isn't changed
+	assert.Equal(t, []*big.Int{big.NewInt(200), big.NewInt(201)}, heads)
+}
+
+func Test_Follower_Reorg(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	m := mock.NewMockStatsOfChangingWebAPI(c)
+	uc := New(m, CountOfBlocks(2))
+	f := NewFollower(uc, logger.SetupLogger("debug"), 0)
+	ctx := context.Background()
+
+	gomock.InOrder(
+		m.EXPECT().GetCurrentBlockNumber(ctx).Return(big.NewInt(200), nil),
+		m.EXPECT().GetCurrentBlockNumber(ctx).Return(big.NewInt(201), nil),
+	)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(199)).
+		Return(linkedBlock("0xh199", "0xh198", "0x1", "0x2", 100), nil)
+	// Block 200 is replaced by reorg, so new block 201 isn't its child
+	gomock.InOrder(
+		m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).
+			Return(linkedBlock("0xh200", "0xh199", "0x2", "0x3", 50), nil),
+		m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).
+			Return(linkedBlock("0xn200", "0xh199", "0x5", "0x4", 70), nil),
+	)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(201)).
+		Return(linkedBlock("0xh201", "0xn200", "0x3", "0x1", 10), nil).Times(2)
+
+	assert.Equal(t, nil, f.step(ctx))
+	assert.Equal(t, nil, f.step(ctx))
+
+	res, err := uc.GetAddressWithBiggestChange(ctx, entity.ChangesQuery{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "0x4", res.Address)
+	assert.Equal(t, "0x46", res.Amount)
+}
+
+func Test_Follower_HotResult(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	m := mock.NewMockStatsOfChangingWebAPI(c)
+	uc := New(m, CountOfBlocks(1), StaleAfter(time.Hour))
+	f := NewFollower(uc, logger.SetupLogger("debug"), 0)
+	ctx := context.Background()
+
+	m.EXPECT().GetCurrentBlockNumber(ctx).Return(big.NewInt(200), nil)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).
+		Return(linkedBlock("0xh200", "0xh199", "0x1", "0x2", 100), nil)
+
+	assert.Equal(t, nil, f.step(ctx))
+
+	// Default query is served without requests to node
+	res, err := uc.GetAddressWithBiggestChange(ctx, entity.ChangesQuery{Metric: entity.MetricNet})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "0x1", res.Address)
+	assert.NotEqual(t, nil, res.ComputedAt)
+	assert.Equal(t, false, res.Stale)
+
+	// Query with parameters of ranking isn't precomputed, but it's computed from followed window
+	res, err = uc.GetAddressWithBiggestChange(ctx, entity.ChangesQuery{Metric: entity.MetricInflow})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "0x2", res.Address)
+	assert.Equal(t, nil, res.ComputedAt)
+}
+
+func Test_Follower_InvalidateHotResult(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	m := mock.NewMockStatsOfChangingWebAPI(c)
+	labels := testLabels{}
+	uc := New(m, CountOfBlocks(1), Labels(labels))
+	f := NewFollower(uc, logger.SetupLogger("debug"), time.Minute)
+	ctx := context.Background()
+	// Default age of stale result is derived from interval of follower
+	assert.Equal(t, 3*time.Minute, uc.staleAfter)
+
+	m.EXPECT().GetCurrentBlockNumber(ctx).Return(big.NewInt(200), nil).Times(2)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).
+		Return(linkedBlock("0xh200", "0xh199", "0x1", "0x2", 100), nil)
+
+	assert.Equal(t, nil, f.step(ctx))
+
+	res, err := uc.GetAddressWithBiggestChange(ctx, entity.ChangesQuery{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, res.Label)
+
+	labels["0x1"] = &entity.Label{Name: "Binance 14", Category: "exchange"}
+	uc.InvalidateHotResult()
+
+	// Dropped result isn't served, so labels are applied to window at once
+	res, err = uc.GetAddressWithBiggestChange(ctx, entity.ChangesQuery{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, labels["0x1"], res.Label)
+	assert.Equal(t, nil, res.ComputedAt)
+	assert.Equal(t, 1, len(f.refresh))
+
+	// Result is computed again even if head isn't changed
+	assert.Equal(t, nil, f.step(ctx))
+
+	res, err = uc.GetAddressWithBiggestChange(ctx, entity.ChangesQuery{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, labels["0x1"], res.Label)
+	assert.NotEqual(t, nil, res.ComputedAt)
+}
+
+func Test_Follower_StaleResult(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	m := mock.NewMockStatsOfChangingWebAPI(c)
+	uc := New(m, CountOfBlocks(1), StaleAfter(time.Nanosecond))
+	f := NewFollower(uc, logger.SetupLogger("debug"), 0)
+	ctx := context.Background()
+
+	m.EXPECT().GetCurrentBlockNumber(ctx).Return(big.NewInt(200), nil)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).
+		Return(linkedBlock("0xh200", "0xh199", "0x1", "0x2", 100), nil)
+
+	assert.Equal(t, nil, f.step(ctx))
+	time.Sleep(time.Millisecond)
+
+	// Stale result is still served, but refresh of follower is requested
+	res, err := uc.GetAddressWithBiggestChange(ctx, entity.ChangesQuery{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "0x1", res.Address)
+	assert.Equal(t, true, res.Stale)
+	assert.Equal(t, 1, len(f.refresh))
+	// Shared result isn't marked
+	assert.Equal(t, false, uc.hot.Load().res.Stale)
+}
+
+// Test is meaningful with -race flag: published window must not be changed by next steps.
+func Test_Follower_ConcurrentReaders(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	m := mock.NewMockStatsOfChangingWebAPI(c)
+	uc := New(m, CountOfBlocks(3))
+	f := NewFollower(uc, logger.SetupLogger("debug"), 0)
+	ctx := context.Background()
+
+	head := int64(200)
+
+	m.EXPECT().GetCurrentBlockNumber(ctx).DoAndReturn(func(context.Context) (*big.Int, error) {
+		head++
+
+		return big.NewInt(head), nil
+	}).AnyTimes()
+	// Addresses are rotated, so some of them leave window and are removed by subtraction
+	m.EXPECT().GetBlockByNumber(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, n *big.Int) (*entity.Block, error) {
+			i := n.Int64()
+
+			return linkedBlock(fmt.Sprintf("0xh%d", i), fmt.Sprintf("0xh%d", i-1),
+				fmt.Sprintf("0x%d", i%5), fmt.Sprintf("0x%d", (i+1)%5), i), nil
+		}).AnyTimes()
+
+	assert.Equal(t, nil, f.step(ctx))
+
+	stop := make(chan struct{})
+
+	var (
+		wg    sync.WaitGroup
+		reads atomic.Int64
+	)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				_, err := uc.GetTopChanges(ctx, entity.ChangesQuery{Metric: entity.MetricGross}, 3)
+				assert.Equal(t, nil, err)
+
+				for _, change := range uc.window.Load().changes.addresses {
+					_ = change.net()
+					_ = len(change.txs)
+				}
+
+				reads.Add(1)
+			}
+		}()
+	}
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, nil, f.step(ctx))
+		// Next step is made only after window is read, so reads overlap with steps
+		for n := reads.Load(); reads.Load() == n; {
+			runtime.Gosched()
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+
+	// Window keeps only transfers of its blocks, each of them is counted by sender and recipient
+	w := uc.window.Load()
+	assert.Equal(t, big.NewInt(head), w.br.last)
+
+	var txCount int64
+	for _, change := range w.changes.addresses {
+		txCount += change.txCount
+	}
+
+	assert.Equal(t, int64(6), txCount)
+}
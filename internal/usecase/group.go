@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Getting grouping of addresses, address grouping is used by default.
+// Entities are ranked only by net change, because only in it transfers inside entity cancel out.
+func resolveGroupBy(groupBy, metric string) (string, error) {
+	switch groupBy = strings.ToLower(groupBy); groupBy {
+	case "", entity.GroupByAddress:
+		return entity.GroupByAddress, nil
+	case entity.GroupByEntity:
+		if metric != entity.MetricNet {
+			return "", fmt.Errorf("%w: %s can't be used with metric %s", entity.ErrInvalidGroupBy, groupBy, metric)
+		}
+
+		return groupBy, nil
+	default:
+		return "", entity.ErrInvalidGroupBy
+	}
+}
+
+// Summing changes of addresses by entities, address without entity makes its own group.
+// Filters are applied to addresses before grouping, so returned ranking doesn't filter groups again.
+func (uc *StatsOfChangingUseCase) groupByEntity(chs *blockChanges, rk *ranking) (*blockChanges, *ranking) {
+	res := newBlockChanges(len(chs.addresses))
+	res.failedTransactions = chs.failedTransactions
+	res.burnedFees = chs.burnedFees
+	res.token = chs.token
+
+	members := make(map[string][]string)
+
+	for addr, change := range chs.addresses {
+		if !rk.allows(addr) {
+			continue
+		}
+
+		key := uc.entity(addr)
+		if key == "" {
+			key = addr
+
+			if chs.isCreatedContract(addr) {
+				res.createdContracts[addr] = struct{}{}
+			}
+		} else {
+			members[key] = append(members[key], addr)
+		}
+		// Flows are summed, so transfers between addresses of entity are both received and sent
+		res.addChange(key, change)
+	}
+
+	for _, addrs := range members {
+		sort.Strings(addrs)
+	}
+
+	return res, &ranking{
+		metric:  rk.metric,
+		groupBy: rk.groupBy,
+		label:   groupLabel(rk.label, members),
+		filters: rk.filters,
+		members: members,
+	}
+}
+
+// Getting labels of groups. Entity IDs share keys with addresses,
+// so they are kept in separate namespace and aren't looked up in labels of addresses.
+func groupLabel(label func(addr string) *entity.Label, members map[string][]string) func(key string) *entity.Label {
+	return func(key string) *entity.Label {
+		if _, ok := members[key]; ok {
+			return nil
+		}
+
+		return label(key)
+	}
+}
+
+// Getting entity and its addresses by key of group, empty values are returned for single address.
+func (r *ranking) group(key string) (string, []string) {
+	addrs, ok := r.members[key]
+	if !ok {
+		return "", nil
+	}
+
+	return key, addrs
+}
+
+// Getting addresses of group, single address makes group by itself.
+func (r *ranking) addresses(key string) []string {
+	if addrs, ok := r.members[key]; ok {
+		return addrs
+	}
+
+	return []string{key}
+}
+
+// Getting entity of address, empty string is returned if address isn't clustered or clusters aren't set.
+func (uc *StatsOfChangingUseCase) entity(addr string) string {
+	if uc.clusters == nil {
+		return ""
+	}
+
+	return uc.clusters.Entity(addr)
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Result is stale if follower missed several steps, so by default it's measured in intervals of follower.
+const _staleFollowSteps = 3
+
+// Precomputed result for default query.
+// It's checked on every step of follower, so it's stale only if follower can't reach head.
+// Result is valid only for version of labels and clusters it was computed with.
+type hotResult struct {
+	res       *entity.BiggestChange
+	checkedAt time.Time
+	version   uint64
+}
+
+// Computing result for default query from followed window and keeping it.
+func (uc *StatsOfChangingUseCase) precompute(ctx context.Context) error {
+	// Version is read before computing, so changes made during it make result invalid
+	version := uc.hotVersion.Load()
+
+	res, err := uc.getAddressWithBiggestChange(ctx, entity.ChangesQuery{})
+	if err != nil {
+		return fmt.Errorf("StatsOfChangingUseCase - precompute - getAddressWithBiggestChange: %w", err)
+	}
+
+	computedAt := time.Now().UTC()
+	res.ComputedAt = &computedAt
+
+	uc.hot.Store(&hotResult{res: res, checkedAt: computedAt, version: version})
+
+	return nil
+}
+
+// Marking precomputed result as actual, when head isn't changed since it was computed.
+// False is returned if there is no valid result, so it must be computed again.
+func (uc *StatsOfChangingUseCase) confirmHotResult() bool {
+	hot := uc.getValidHotResult()
+	if hot == nil {
+		return false
+	}
+
+	uc.hot.Store(&hotResult{res: hot.res, checkedAt: time.Now().UTC(), version: hot.version})
+
+	return true
+}
+
+// Dropping precomputed result, it's called when labels or clusters of addresses are changed.
+// Follower is asked to compute result again, until then default query is computed from window.
+func (uc *StatsOfChangingUseCase) InvalidateHotResult() {
+	uc.hotVersion.Add(1)
+
+	if uc.revalidate != nil {
+		uc.revalidate()
+	}
+}
+
+// Getting precomputed result if it was computed with current labels and clusters.
+func (uc *StatsOfChangingUseCase) getValidHotResult() *hotResult {
+	hot := uc.hot.Load()
+	if hot == nil || hot.version != uc.hotVersion.Load() {
+		return nil
+	}
+
+	return hot
+}
+
+// Getting precomputed result if query is default, otherwise nil is returned.
+// Result which is older than staleAfter is still returned, but it's marked and refreshed in background.
+func (uc *StatsOfChangingUseCase) getHotResult(query entity.ChangesQuery) *entity.BiggestChange {
+	if !uc.isDefaultQuery(query) {
+		return nil
+	}
+
+	hot := uc.getValidHotResult()
+	if hot == nil {
+		return nil
+	}
+	// Precomputed result is shared, so only its copy is marked
+	res := *hot.res
+	if time.Since(hot.checkedAt) > uc.staleAfter {
+		res.Stale = true
+
+		uc.revalidate()
+	}
+
+	return &res
+}
+
+// Checking that query describes default window without any parameters of ranking.
+func (uc *StatsOfChangingUseCase) isDefaultQuery(query entity.ChangesQuery) bool {
+	if query.CountOfBlocks == uc.countOfBlocks {
+		query.CountOfBlocks = 0
+	}
+
+	if query.Anchor == uc.anchor {
+		query.Anchor = ""
+	}
+
+	if query.Metric == entity.MetricNet {
+		query.Metric = ""
+	}
+
+	return reflect.DeepEqual(query, entity.ChangesQuery{})
+}
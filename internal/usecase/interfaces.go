@@ -4,6 +4,7 @@ package usecase
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/egor-denisov/biggest-change/internal/entity"
 )
@@ -12,11 +13,48 @@ import (
 
 type (
 	StatsOfChanging interface {
-		GetAddressWithBiggestChange(ctx context.Context, countOfLastBlocks uint) (*entity.BiggestChange, error)
+		GetAddressWithBiggestChange(ctx context.Context, query entity.ChangesQuery) (*entity.BiggestChange, error)
+		GetTopChanges(ctx context.Context, query entity.ChangesQuery, limit uint) (*entity.TopChanges, error)
+		GetAddressChanges(
+			ctx context.Context,
+			address string,
+			query entity.ChangesQuery,
+		) (*entity.AddressChanges, error)
+		GetBlockAtTime(ctx context.Context, t time.Time) (*entity.BlockAtTime, error)
 	}
 
 	StatsOfChangingWebAPI interface {
-		GetTransactionsByBlockNumber(ctx context.Context, blockNumber *big.Int) ([]*entity.Transaction, error)
+		GetBlockByNumber(ctx context.Context, blockNumber *big.Int) (*entity.Block, error)
+		GetBlockHeader(ctx context.Context, blockNumber *big.Int) (*entity.BlockHeader, error)
+		GetBlockNumberByTag(ctx context.Context, tag string) (*big.Int, error)
 		GetCurrentBlockNumber(ctx context.Context) (*big.Int, error)
+		GetTokenTransfers(
+			ctx context.Context,
+			token string,
+			fromBlock, toBlock *big.Int,
+		) ([]*entity.TokenTransfer, error)
+		GetTokenDecimals(ctx context.Context, token string) (uint8, error)
+		GetBalance(ctx context.Context, address string, blockNumber *big.Int) (*big.Int, error)
+		GetTokenBalance(ctx context.Context, token, address string, blockNumber *big.Int) (*big.Int, error)
+	}
+
+	LabelStore interface {
+		Label(address string) *entity.Label
+	}
+
+	ClusterStore interface {
+		Entity(address string) string
+	}
+
+	ClusterRegistry interface {
+		GetClusters() map[string][]string
+		SetCluster(entityID string, addresses []string) error
+		DeleteCluster(entityID string) error
+	}
+
+	AlertRegistry interface {
+		GetRules() []entity.AlertRule
+		SetRule(rule entity.AlertRule) error
+		DeleteRule(id string) error
 	}
 )
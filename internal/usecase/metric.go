@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Checking metric by which addresses are ranked, net change is used by default.
+func resolveMetric(metric string) (string, error) {
+	switch metric {
+	case "":
+		return entity.MetricNet, nil
+	case entity.MetricNet, entity.MetricInflow, entity.MetricOutflow, entity.MetricGross, entity.MetricTxCount,
+		entity.MetricRelative:
+		return metric, nil
+	default:
+		return "", fmt.Errorf("resolveMetric - %q: %w", metric, entity.ErrInvalidMetric)
+	}
+}
+
+// Getting value of change by metric, only net change can be negative.
+// Outflow includes fees, because they leave balance like sent value.
+func (c *addressChange) value(metric string) *big.Int {
+	switch metric {
+	case entity.MetricInflow:
+		return new(big.Int).Set(c.received)
+	case entity.MetricOutflow:
+		return new(big.Int).Add(c.sent, c.fees)
+	case entity.MetricGross:
+		res := new(big.Int).Add(c.received, c.sent)
+
+		return res.Add(res, c.fees)
+	case entity.MetricTxCount:
+		return big.NewInt(c.txCount)
+	default:
+		return c.net()
+	}
+}
@@ -8,6 +8,7 @@ import (
 	context "context"
 	big "math/big"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/egor-denisov/biggest-change/internal/entity"
 	gomock "github.com/golang/mock/gomock"
@@ -36,19 +37,64 @@ func (m *MockStatsOfChanging) EXPECT() *MockStatsOfChangingMockRecorder {
 	return m.recorder
 }
 
+// GetAddressChanges mocks base method.
+func (m *MockStatsOfChanging) GetAddressChanges(ctx context.Context, address string, query entity.ChangesQuery) (*entity.AddressChanges, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddressChanges", ctx, address, query)
+	ret0, _ := ret[0].(*entity.AddressChanges)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddressChanges indicates an expected call of GetAddressChanges.
+func (mr *MockStatsOfChangingMockRecorder) GetAddressChanges(ctx, address, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressChanges", reflect.TypeOf((*MockStatsOfChanging)(nil).GetAddressChanges), ctx, address, query)
+}
+
 // GetAddressWithBiggestChange mocks base method.
-func (m *MockStatsOfChanging) GetAddressWithBiggestChange(ctx context.Context, countOfLastBlocks uint) (*entity.BiggestChange, error) {
+func (m *MockStatsOfChanging) GetAddressWithBiggestChange(ctx context.Context, query entity.ChangesQuery) (*entity.BiggestChange, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAddressWithBiggestChange", ctx, countOfLastBlocks)
+	ret := m.ctrl.Call(m, "GetAddressWithBiggestChange", ctx, query)
 	ret0, _ := ret[0].(*entity.BiggestChange)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAddressWithBiggestChange indicates an expected call of GetAddressWithBiggestChange.
-func (mr *MockStatsOfChangingMockRecorder) GetAddressWithBiggestChange(ctx, countOfLastBlocks interface{}) *gomock.Call {
+func (mr *MockStatsOfChangingMockRecorder) GetAddressWithBiggestChange(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressWithBiggestChange", reflect.TypeOf((*MockStatsOfChanging)(nil).GetAddressWithBiggestChange), ctx, query)
+}
+
+// GetBlockAtTime mocks base method.
+func (m *MockStatsOfChanging) GetBlockAtTime(ctx context.Context, t time.Time) (*entity.BlockAtTime, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockAtTime", ctx, t)
+	ret0, _ := ret[0].(*entity.BlockAtTime)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockAtTime indicates an expected call of GetBlockAtTime.
+func (mr *MockStatsOfChangingMockRecorder) GetBlockAtTime(ctx, t interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddressWithBiggestChange", reflect.TypeOf((*MockStatsOfChanging)(nil).GetAddressWithBiggestChange), ctx, countOfLastBlocks)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockAtTime", reflect.TypeOf((*MockStatsOfChanging)(nil).GetBlockAtTime), ctx, t)
+}
+
+// GetTopChanges mocks base method.
+func (m *MockStatsOfChanging) GetTopChanges(ctx context.Context, query entity.ChangesQuery, limit uint) (*entity.TopChanges, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopChanges", ctx, query, limit)
+	ret0, _ := ret[0].(*entity.TopChanges)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopChanges indicates an expected call of GetTopChanges.
+func (mr *MockStatsOfChangingMockRecorder) GetTopChanges(ctx, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopChanges", reflect.TypeOf((*MockStatsOfChanging)(nil).GetTopChanges), ctx, query, limit)
 }
 
 // MockStatsOfChangingWebAPI is a mock of StatsOfChangingWebAPI interface.
@@ -74,6 +120,66 @@ func (m *MockStatsOfChangingWebAPI) EXPECT() *MockStatsOfChangingWebAPIMockRecor
 	return m.recorder
 }
 
+// GetBalance mocks base method.
+func (m *MockStatsOfChangingWebAPI) GetBalance(ctx context.Context, address string, blockNumber *big.Int) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", ctx, address, blockNumber)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockStatsOfChangingWebAPIMockRecorder) GetBalance(ctx, address, blockNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockStatsOfChangingWebAPI)(nil).GetBalance), ctx, address, blockNumber)
+}
+
+// GetBlockByNumber mocks base method.
+func (m *MockStatsOfChangingWebAPI) GetBlockByNumber(ctx context.Context, blockNumber *big.Int) (*entity.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockByNumber", ctx, blockNumber)
+	ret0, _ := ret[0].(*entity.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockByNumber indicates an expected call of GetBlockByNumber.
+func (mr *MockStatsOfChangingWebAPIMockRecorder) GetBlockByNumber(ctx, blockNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByNumber", reflect.TypeOf((*MockStatsOfChangingWebAPI)(nil).GetBlockByNumber), ctx, blockNumber)
+}
+
+// GetBlockHeader mocks base method.
+func (m *MockStatsOfChangingWebAPI) GetBlockHeader(ctx context.Context, blockNumber *big.Int) (*entity.BlockHeader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockHeader", ctx, blockNumber)
+	ret0, _ := ret[0].(*entity.BlockHeader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockHeader indicates an expected call of GetBlockHeader.
+func (mr *MockStatsOfChangingWebAPIMockRecorder) GetBlockHeader(ctx, blockNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHeader", reflect.TypeOf((*MockStatsOfChangingWebAPI)(nil).GetBlockHeader), ctx, blockNumber)
+}
+
+// GetBlockNumberByTag mocks base method.
+func (m *MockStatsOfChangingWebAPI) GetBlockNumberByTag(ctx context.Context, tag string) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockNumberByTag", ctx, tag)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockNumberByTag indicates an expected call of GetBlockNumberByTag.
+func (mr *MockStatsOfChangingWebAPIMockRecorder) GetBlockNumberByTag(ctx, tag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockNumberByTag", reflect.TypeOf((*MockStatsOfChangingWebAPI)(nil).GetBlockNumberByTag), ctx, tag)
+}
+
 // GetCurrentBlockNumber mocks base method.
 func (m *MockStatsOfChangingWebAPI) GetCurrentBlockNumber(ctx context.Context) (*big.Int, error) {
 	m.ctrl.T.Helper()
@@ -89,17 +195,251 @@ func (mr *MockStatsOfChangingWebAPIMockRecorder) GetCurrentBlockNumber(ctx inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentBlockNumber", reflect.TypeOf((*MockStatsOfChangingWebAPI)(nil).GetCurrentBlockNumber), ctx)
 }
 
-// GetTransactionsByBlockNumber mocks base method.
-func (m *MockStatsOfChangingWebAPI) GetTransactionsByBlockNumber(ctx context.Context, blockNumber *big.Int) ([]*entity.Transaction, error) {
+// GetTokenBalance mocks base method.
+func (m *MockStatsOfChangingWebAPI) GetTokenBalance(ctx context.Context, token, address string, blockNumber *big.Int) (*big.Int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionsByBlockNumber", ctx, blockNumber)
-	ret0, _ := ret[0].([]*entity.Transaction)
+	ret := m.ctrl.Call(m, "GetTokenBalance", ctx, token, address, blockNumber)
+	ret0, _ := ret[0].(*big.Int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransactionsByBlockNumber indicates an expected call of GetTransactionsByBlockNumber.
-func (mr *MockStatsOfChangingWebAPIMockRecorder) GetTransactionsByBlockNumber(ctx, blockNumber interface{}) *gomock.Call {
+// GetTokenBalance indicates an expected call of GetTokenBalance.
+func (mr *MockStatsOfChangingWebAPIMockRecorder) GetTokenBalance(ctx, token, address, blockNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenBalance", reflect.TypeOf((*MockStatsOfChangingWebAPI)(nil).GetTokenBalance), ctx, token, address, blockNumber)
+}
+
+// GetTokenDecimals mocks base method.
+func (m *MockStatsOfChangingWebAPI) GetTokenDecimals(ctx context.Context, token string) (uint8, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenDecimals", ctx, token)
+	ret0, _ := ret[0].(uint8)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenDecimals indicates an expected call of GetTokenDecimals.
+func (mr *MockStatsOfChangingWebAPIMockRecorder) GetTokenDecimals(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenDecimals", reflect.TypeOf((*MockStatsOfChangingWebAPI)(nil).GetTokenDecimals), ctx, token)
+}
+
+// GetTokenTransfers mocks base method.
+func (m *MockStatsOfChangingWebAPI) GetTokenTransfers(ctx context.Context, token string, fromBlock, toBlock *big.Int) ([]*entity.TokenTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenTransfers", ctx, token, fromBlock, toBlock)
+	ret0, _ := ret[0].([]*entity.TokenTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenTransfers indicates an expected call of GetTokenTransfers.
+func (mr *MockStatsOfChangingWebAPIMockRecorder) GetTokenTransfers(ctx, token, fromBlock, toBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenTransfers", reflect.TypeOf((*MockStatsOfChangingWebAPI)(nil).GetTokenTransfers), ctx, token, fromBlock, toBlock)
+}
+
+// MockLabelStore is a mock of LabelStore interface.
+type MockLabelStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockLabelStoreMockRecorder
+}
+
+// MockLabelStoreMockRecorder is the mock recorder for MockLabelStore.
+type MockLabelStoreMockRecorder struct {
+	mock *MockLabelStore
+}
+
+// NewMockLabelStore creates a new mock instance.
+func NewMockLabelStore(ctrl *gomock.Controller) *MockLabelStore {
+	mock := &MockLabelStore{ctrl: ctrl}
+	mock.recorder = &MockLabelStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLabelStore) EXPECT() *MockLabelStoreMockRecorder {
+	return m.recorder
+}
+
+// Label mocks base method.
+func (m *MockLabelStore) Label(address string) *entity.Label {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Label", address)
+	ret0, _ := ret[0].(*entity.Label)
+	return ret0
+}
+
+// Label indicates an expected call of Label.
+func (mr *MockLabelStoreMockRecorder) Label(address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Label", reflect.TypeOf((*MockLabelStore)(nil).Label), address)
+}
+
+// MockClusterStore is a mock of ClusterStore interface.
+type MockClusterStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockClusterStoreMockRecorder
+}
+
+// MockClusterStoreMockRecorder is the mock recorder for MockClusterStore.
+type MockClusterStoreMockRecorder struct {
+	mock *MockClusterStore
+}
+
+// NewMockClusterStore creates a new mock instance.
+func NewMockClusterStore(ctrl *gomock.Controller) *MockClusterStore {
+	mock := &MockClusterStore{ctrl: ctrl}
+	mock.recorder = &MockClusterStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClusterStore) EXPECT() *MockClusterStoreMockRecorder {
+	return m.recorder
+}
+
+// Entity mocks base method.
+func (m *MockClusterStore) Entity(address string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Entity", address)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Entity indicates an expected call of Entity.
+func (mr *MockClusterStoreMockRecorder) Entity(address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Entity", reflect.TypeOf((*MockClusterStore)(nil).Entity), address)
+}
+
+// MockClusterRegistry is a mock of ClusterRegistry interface.
+type MockClusterRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockClusterRegistryMockRecorder
+}
+
+// MockClusterRegistryMockRecorder is the mock recorder for MockClusterRegistry.
+type MockClusterRegistryMockRecorder struct {
+	mock *MockClusterRegistry
+}
+
+// NewMockClusterRegistry creates a new mock instance.
+func NewMockClusterRegistry(ctrl *gomock.Controller) *MockClusterRegistry {
+	mock := &MockClusterRegistry{ctrl: ctrl}
+	mock.recorder = &MockClusterRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClusterRegistry) EXPECT() *MockClusterRegistryMockRecorder {
+	return m.recorder
+}
+
+// DeleteCluster mocks base method.
+func (m *MockClusterRegistry) DeleteCluster(entityID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCluster", entityID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCluster indicates an expected call of DeleteCluster.
+func (mr *MockClusterRegistryMockRecorder) DeleteCluster(entityID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCluster", reflect.TypeOf((*MockClusterRegistry)(nil).DeleteCluster), entityID)
+}
+
+// GetClusters mocks base method.
+func (m *MockClusterRegistry) GetClusters() map[string][]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusters")
+	ret0, _ := ret[0].(map[string][]string)
+	return ret0
+}
+
+// GetClusters indicates an expected call of GetClusters.
+func (mr *MockClusterRegistryMockRecorder) GetClusters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusters", reflect.TypeOf((*MockClusterRegistry)(nil).GetClusters))
+}
+
+// SetCluster mocks base method.
+func (m *MockClusterRegistry) SetCluster(entityID string, addresses []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCluster", entityID, addresses)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCluster indicates an expected call of SetCluster.
+func (mr *MockClusterRegistryMockRecorder) SetCluster(entityID, addresses interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCluster", reflect.TypeOf((*MockClusterRegistry)(nil).SetCluster), entityID, addresses)
+}
+
+// MockAlertRegistry is a mock of AlertRegistry interface.
+type MockAlertRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertRegistryMockRecorder
+}
+
+// MockAlertRegistryMockRecorder is the mock recorder for MockAlertRegistry.
+type MockAlertRegistryMockRecorder struct {
+	mock *MockAlertRegistry
+}
+
+// NewMockAlertRegistry creates a new mock instance.
+func NewMockAlertRegistry(ctrl *gomock.Controller) *MockAlertRegistry {
+	mock := &MockAlertRegistry{ctrl: ctrl}
+	mock.recorder = &MockAlertRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertRegistry) EXPECT() *MockAlertRegistryMockRecorder {
+	return m.recorder
+}
+
+// DeleteRule mocks base method.
+func (m *MockAlertRegistry) DeleteRule(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRule", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockAlertRegistryMockRecorder) DeleteRule(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockAlertRegistry)(nil).DeleteRule), id)
+}
+
+// GetRules mocks base method.
+func (m *MockAlertRegistry) GetRules() []entity.AlertRule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRules")
+	ret0, _ := ret[0].([]entity.AlertRule)
+	return ret0
+}
+
+// GetRules indicates an expected call of GetRules.
+func (mr *MockAlertRegistryMockRecorder) GetRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRules", reflect.TypeOf((*MockAlertRegistry)(nil).GetRules))
+}
+
+// SetRule mocks base method.
+func (m *MockAlertRegistry) SetRule(rule entity.AlertRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRule", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRule indicates an expected call of SetRule.
+func (mr *MockAlertRegistryMockRecorder) SetRule(rule interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByBlockNumber", reflect.TypeOf((*MockStatsOfChangingWebAPI)(nil).GetTransactionsByBlockNumber), ctx, blockNumber)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRule", reflect.TypeOf((*MockAlertRegistry)(nil).SetRule), rule)
 }
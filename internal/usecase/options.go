@@ -1,5 +1,7 @@
 package usecase
 
+import "time"
+
 type Option func(*StatsOfChangingUseCase)
 
 func CacheSize(cacheSize int) Option {
@@ -8,6 +10,12 @@ func CacheSize(cacheSize int) Option {
 	}
 }
 
+func HeaderCacheSize(headerCacheSize int) Option {
+	return func(uc *StatsOfChangingUseCase) {
+		uc.headerCacheSize = headerCacheSize
+	}
+}
+
 func MaxGoroutines(maxGoroutines int) Option {
 	return func(s *StatsOfChangingUseCase) {
 		s.maxGoroutines = maxGoroutines
@@ -24,3 +32,75 @@ func CountOfBlocks(countOfBlocks uint) Option {
 		s.countOfBlocks = countOfBlocks
 	}
 }
+
+func MaxCountOfBlocks(maxCountOfBlocks uint) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.maxCountOfBlocks = maxCountOfBlocks
+	}
+}
+
+func TopLimit(topLimit uint) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.topLimit = topLimit
+	}
+}
+
+func MaxTopLimit(maxTopLimit uint) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.maxTopLimit = maxTopLimit
+	}
+}
+
+func ConfirmationDepth(confirmationDepth uint) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.confirmationDepth = confirmationDepth
+	}
+}
+
+func Anchor(anchor string) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.anchor = anchor
+	}
+}
+
+func Exclude(addresses []string) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.exclude = excludeSet(addresses)
+	}
+}
+
+func Labels(labels LabelStore) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.labels = labels
+	}
+}
+
+func RelativeCandidates(relativeCandidates int) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.relativeCandidates = relativeCandidates
+	}
+}
+
+func RelativeMinBalance(relativeMinBalance float64) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.relativeMinBalance = relativeMinBalance
+	}
+}
+
+func StaleAfter(staleAfter time.Duration) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.staleAfter = staleAfter
+	}
+}
+
+func Clusters(clusters ClusterStore) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.clusters = clusters
+	}
+}
+
+func Withdrawals(withdrawals bool) Option {
+	return func(s *StatsOfChangingUseCase) {
+		s.withdrawals = withdrawals
+	}
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Parameters by which addresses are ranked.
+type ranking struct {
+	metric            string
+	groupBy           string
+	include           map[string]struct{}
+	exclude           map[string]struct{}
+	categories        map[string]struct{}
+	excludeCategories map[string]struct{}
+	label             func(addr string) *entity.Label
+	filters           *entity.AddressFilters
+	members           map[string][]string // Addresses of entities, set only if addresses are grouped
+	scores            map[string]*big.Int // Relative changes of candidates, set only for relative metric
+	balances          map[string]*balance
+}
+
+// Getting ranking described by query.
+// Addresses excluded by config are always skipped, filters are nil if nothing is filtered.
+func (uc *StatsOfChangingUseCase) getRanking(query entity.ChangesQuery) (*ranking, error) {
+	metric, err := resolveMetric(query.Metric)
+	if err != nil {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - getRanking - resolveMetric: %w", err)
+	}
+
+	groupBy, err := resolveGroupBy(query.GroupBy, metric)
+	if err != nil {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - getRanking - resolveGroupBy: %w", err)
+	}
+
+	include, err := normalizeAddresses(query.Include)
+	if err != nil {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - getRanking - normalizeAddresses: %w", err)
+	}
+
+	exclude, err := normalizeAddresses(query.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - getRanking - normalizeAddresses: %w", err)
+	}
+
+	categories := normalizeCategories(query.Categories)
+	excludeCategories := normalizeCategories(query.ExcludeCategories)
+
+	res := &ranking{
+		metric:            metric,
+		groupBy:           groupBy,
+		include:           toSet(include),
+		exclude:           toSet(exclude),
+		categories:        toSet(categories),
+		excludeCategories: toSet(excludeCategories),
+		label:             uc.label,
+	}
+
+	for addr := range uc.exclude {
+		res.exclude[addr] = struct{}{}
+	}
+
+	if len(res.include) != 0 || len(res.exclude) != 0 || len(categories) != 0 || len(excludeCategories) != 0 {
+		res.filters = &entity.AddressFilters{
+			Include:           include,
+			Exclude:           exclude,
+			Categories:        categories,
+			ExcludeCategories: excludeCategories,
+			ExcludedByConfig:  len(uc.exclude),
+		}
+	}
+
+	return res, nil
+}
+
+// Checking that address can be ranked.
+func (r *ranking) allows(addr string) bool {
+	if _, ok := r.exclude[addr]; ok {
+		return false
+	}
+
+	if len(r.include) != 0 {
+		if _, ok := r.include[addr]; !ok {
+			return false
+		}
+	}
+
+	if len(r.categories) == 0 && len(r.excludeCategories) == 0 {
+		return true
+	}
+	// Addresses without label have empty category
+	var category string
+	if label := r.label(addr); label != nil {
+		category = label.Category
+	}
+
+	if _, ok := r.excludeCategories[category]; ok {
+		return false
+	}
+
+	if len(r.categories) == 0 {
+		return true
+	}
+
+	_, ok := r.categories[category]
+
+	return ok
+}
+
+// Getting label of address, nil is returned if there is no label or labels aren't set.
+func (uc *StatsOfChangingUseCase) label(addr string) *entity.Label {
+	if uc.labels == nil {
+		return nil
+	}
+
+	return uc.labels.Label(addr)
+}
+
+// Validating addresses and removing duplicates, result is sorted to make response deterministic.
+func normalizeAddresses(addrs []string) ([]string, error) {
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+
+	set := make(map[string]struct{}, len(addrs))
+
+	for _, addr := range addrs {
+		res, err := entity.NormalizeAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		set[res] = struct{}{}
+	}
+
+	res := make([]string, 0, len(set))
+	for addr := range set {
+		res = append(res, addr)
+	}
+
+	sort.Strings(res)
+
+	return res, nil
+}
+
+// Bringing categories to lower case and removing duplicates, result is sorted like addresses.
+func normalizeCategories(categories []string) []string {
+	set := make(map[string]struct{}, len(categories))
+
+	for _, c := range categories {
+		if c = strings.ToLower(strings.TrimSpace(c)); c != "" {
+			set[c] = struct{}{}
+		}
+	}
+
+	if len(set) == 0 {
+		return nil
+	}
+
+	res := make([]string, 0, len(set))
+	for c := range set {
+		res = append(res, c)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func toSet(values []string) map[string]struct{} {
+	res := make(map[string]struct{}, len(values))
+
+	for _, v := range values {
+		res[v] = struct{}{}
+	}
+
+	return res
+}
+
+// Bringing addresses from config to lower case, they are validated while config is loaded.
+func excludeSet(addrs []string) map[string]struct{} {
+	res := make(map[string]struct{}, len(addrs))
+
+	for _, addr := range addrs {
+		res[strings.ToLower(strings.TrimSpace(addr))] = struct{}{}
+	}
+
+	return res
+}
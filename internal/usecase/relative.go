@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+const (
+	_defaultRelativeCandidates = 100
+	_defaultRelativeMinBalance = 1.0 // In ether or in whole tokens
+	_etherDecimals             = 18
+	// Relative change is kept as fixed-point number, so it can be compared like other metrics.
+	_relativeScale = 18
+)
+
+// Scoring addresses by net change relative to balance before window.
+// Balance is raised to minimal balance, so addresses with almost empty balance don't get huge scores.
+// Balances are loaded only for candidates with biggest net change, because each address costs two requests.
+func (uc *StatsOfChangingUseCase) setRelativeScores(
+	ctx context.Context,
+	chs *blockChanges,
+	br *blockRange,
+	rk *ranking,
+	limit int,
+) error {
+	candidates := uc.getRelativeCandidates(chs, rk, limit)
+
+	if err := uc.loadBalances(ctx, candidates, br, chs.token, rk); err != nil {
+		return fmt.Errorf("StatsOfChangingUseCase - setRelativeScores - loadBalances: %w", err)
+	}
+
+	minBalance := uc.minBalance(chs.token)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(_relativeScale), nil)
+
+	rk.scores = make(map[string]*big.Int, len(candidates))
+
+	for _, addr := range candidates {
+		base := rk.balances[addr].start
+		if base.Cmp(minBalance) < 0 {
+			base = minBalance
+		}
+		// Without minimal balance change of empty address can't be scored
+		if base.Sign() == 0 {
+			continue
+		}
+
+		score := new(big.Int).Mul(chs.addresses[addr].net(), scale)
+		rk.scores[addr] = score.Quo(score, base)
+	}
+
+	return nil
+}
+
+// Getting addresses with biggest net change, which are scored by relative change.
+func (uc *StatsOfChangingUseCase) getRelativeCandidates(chs *blockChanges, rk *ranking, limit int) []string {
+	amounts := make(map[string]*big.Int, len(chs.addresses))
+	res := make([]string, 0, len(chs.addresses))
+
+	for addr, change := range chs.addresses {
+		if !rk.allows(addr) {
+			continue
+		}
+
+		if amount := change.net(); amount.Sign() != 0 {
+			amounts[addr] = amount
+			res = append(res, addr)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return isBiggerChange(res[i], amounts[res[i]], res[j], amounts[res[j]])
+	})
+
+	if count := max(limit, uc.relativeCandidates); len(res) > count {
+		res = res[:count]
+	}
+
+	return res
+}
+
+// Getting minimal balance in wei or in base units of token.
+func (uc *StatsOfChangingUseCase) minBalance(token *entity.Token) *big.Int {
+	decimals := int64(_etherDecimals)
+	if token != nil {
+		decimals = int64(token.Decimals)
+	}
+
+	unit := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil))
+	// Rounding to nearest unit, because fraction of ether isn't exact in float
+	unit.Mul(unit, big.NewFloat(uc.relativeMinBalance)).Add(unit, big.NewFloat(0.5))
+	res, _ := unit.Int(nil)
+
+	return res
+}
+
+// Getting amount of address in response, for relative metric it's net change instead of score.
+func (r *ranking) amount(addr string, chs *blockChanges, value *big.Int) *big.Int {
+	if r.metric == entity.MetricRelative {
+		return chs.addresses[addr].net()
+	}
+
+	return value
+}
+
+// Getting value by which address is ranked, nil is returned if address can't be ranked.
+func (r *ranking) value(addr string, change *addressChange) *big.Int {
+	if r.metric == entity.MetricRelative {
+		return r.scores[addr]
+	}
+
+	return change.value(r.metric)
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Count of attempts to refetch orphaned blocks before giving up.
+const _maxReorgRetries = 3
+
+var reorgsTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "biggest_change_reorgs_total",
+	Help: "Count of detected chain reorganizations in cached blocks.",
+})
+
+// Checking that blocks of range are linked by parent hashes.
+// Blocks around every broken link are evicted from cache and fetched again.
+func (uc *StatsOfChangingUseCase) verifyChain(
+	ctx context.Context,
+	br *blockRange,
+	blocks []*blockChanges,
+) error {
+	for attempt := 0; ; attempt++ {
+		orphaned := findUnlinkedBlocks(blocks)
+		if len(orphaned) == 0 {
+			return nil
+		}
+
+		if attempt == _maxReorgRetries {
+			return fmt.Errorf("StatsOfChangingUseCase - verifyChain: %w", entity.ErrChainReorganized)
+		}
+
+		reorgsTotal.Inc()
+
+		for _, offset := range orphaned {
+			blockNumber := new(big.Int).Add(br.first, big.NewInt(int64(offset)))
+			uc.cache.Remove(blockNumber.String())
+
+			chs, err := uc.getAddressWithChanges(ctx, blockNumber)
+			if err != nil {
+				return fmt.Errorf("StatsOfChangingUseCase - verifyChain - getAddressWithChanges: %w", err)
+			}
+
+			blocks[offset] = chs
+		}
+	}
+}
+
+// Getting offsets of blocks on both sides of every broken link.
+// It isn't known which side is stale, so both of them should be refetched.
+func findUnlinkedBlocks(blocks []*blockChanges) []int {
+	var res []int
+
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i].isChildOf(blocks[i-1]) {
+			continue
+		}
+
+		if len(res) == 0 || res[len(res)-1] != i-1 {
+			res = append(res, i-1)
+		}
+
+		res = append(res, i)
+	}
+
+	return res
+}
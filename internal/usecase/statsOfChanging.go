@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/egor-denisov/biggest-change/internal/entity"
 	lru "github.com/hashicorp/golang-lru"
@@ -14,25 +17,59 @@ const (
 	_defaultMaxGoroutines                   = 50
 	_defaultAverageAddressCountInBlock      = 200
 	_defaultCacheSize                       = 100 // Count of blocks for which the transaction value will be cached
+	_defaultHeaderCacheSize                 = 1000
 	_defaultCountOfBlocks              uint = 100
+	_defaultMaxCountOfBlocks           uint = 10000
+	_defaultTopLimit                   uint = 10
+	_defaultMaxTopLimit                uint = 100
+	_defaultWithdrawals                     = true
+	_defaultAnchor                          = entity.AnchorLatest
 )
 
 type StatsOfChangingUseCase struct {
 	webAPI                     StatsOfChangingWebAPI
 	cache                      *lru.Cache
 	cacheSize                  int
+	headerCache                *lru.Cache
+	headerCacheSize            int
 	maxGoroutines              int
 	averageAddressCountInBlock int
 	countOfBlocks              uint
+	maxCountOfBlocks           uint
+	topLimit                   uint
+	maxTopLimit                uint
+	withdrawals                bool
+	confirmationDepth          uint
+	anchor                     string
+	exclude                    map[string]struct{}
+	labels                     LabelStore
+	clusters                   ClusterStore
+	relativeCandidates         int
+	relativeMinBalance         float64
+	tokenDecimals              sync.Map
+	window                     atomic.Pointer[followedWindow]
+	hot                        atomic.Pointer[hotResult]
+	hotVersion                 atomic.Uint64
+	staleAfter                 time.Duration
+	revalidate                 func()
 }
 
 func New(w StatsOfChangingWebAPI, opts ...Option) *StatsOfChangingUseCase {
 	uc := &StatsOfChangingUseCase{
 		webAPI:                     w,
 		cacheSize:                  _defaultCacheSize,
+		headerCacheSize:            _defaultHeaderCacheSize,
 		maxGoroutines:              _defaultMaxGoroutines,
 		averageAddressCountInBlock: _defaultAverageAddressCountInBlock,
 		countOfBlocks:              _defaultCountOfBlocks,
+		maxCountOfBlocks:           _defaultMaxCountOfBlocks,
+		topLimit:                   _defaultTopLimit,
+		maxTopLimit:                _defaultMaxTopLimit,
+		withdrawals:                _defaultWithdrawals,
+		anchor:                     _defaultAnchor,
+		relativeCandidates:         _defaultRelativeCandidates,
+		relativeMinBalance:         _defaultRelativeMinBalance,
+		revalidate:                 func() {},
 	}
 
 	for _, opt := range opts {
@@ -40,69 +77,216 @@ func New(w StatsOfChangingWebAPI, opts ...Option) *StatsOfChangingUseCase {
 	}
 
 	uc.cache, _ = lru.New(uc.cacheSize)
+	uc.headerCache, _ = lru.New(uc.headerCacheSize)
 
 	return uc
 }
 
-// Get address with biggest change in last countOfLastBlocks blocks.
+// Get address with biggest change in blocks described by query.
+// Result for default query is precomputed, so it's returned without computing.
 func (uc *StatsOfChangingUseCase) GetAddressWithBiggestChange(
 	ctx context.Context,
-	countOfLastBlocks uint,
+	query entity.ChangesQuery,
 ) (*entity.BiggestChange, error) {
-	if countOfLastBlocks == 0 {
-		countOfLastBlocks = uc.countOfBlocks
+	if res := uc.getHotResult(query); res != nil {
+		return res, nil
 	}
-	// Getting current number of block.
-	currentBlock, err := uc.webAPI.GetCurrentBlockNumber(ctx)
+
+	return uc.getAddressWithBiggestChange(ctx, query)
+}
+
+// Computing address with biggest change in blocks described by query.
+func (uc *StatsOfChangingUseCase) getAddressWithBiggestChange(
+	ctx context.Context,
+	query entity.ChangesQuery,
+) (*entity.BiggestChange, error) {
+	rk, err := uc.getRanking(query)
 	if err != nil {
 		return nil,
-			fmt.Errorf("StatsOfChangingUseCase - GetAddressWithBiggestChange - uc.webAPI.GetCurrentBlockNumber: %w", err)
+			fmt.Errorf("StatsOfChangingUseCase - getAddressWithBiggestChange - uc.getRanking: %w", err)
 	}
-	// Getting map which store addresses and changes in last countOfLastBlocks blocks.
-	addresses, err := uc.getAddressChangeMap(ctx, currentBlock, int(countOfLastBlocks))
+	// Getting range of blocks and map which store addresses and changes in it.
+	br, addresses, err := uc.getWindow(ctx, query)
 	if err != nil {
 		return nil,
-			fmt.Errorf("StatsOfChangingUseCase - GetAddressWithBiggestChange - getAddressChangeMap: %w", err)
+			fmt.Errorf("StatsOfChangingUseCase - getAddressWithBiggestChange - getWindow: %w", err)
+	}
+	// Summing changes of addresses of one entity if it's requested
+	if rk.groupBy == entity.GroupByEntity {
+		addresses, rk = uc.groupByEntity(addresses, rk)
+	}
+	// Relative metric needs balances of candidates before ranking
+	if rk.metric == entity.MetricRelative {
+		if err := uc.setRelativeScores(ctx, addresses, br, rk, 1); err != nil {
+			return nil,
+				fmt.Errorf("StatsOfChangingUseCase - getAddressWithBiggestChange - setRelativeScores: %w", err)
+		}
+	}
+
+	res := uc.getMaxChanging(addresses, br, rk)
+	// Balances are always known for relative metric, otherwise they are loaded only on request
+	if query.Balances || rk.metric == entity.MetricRelative {
+		if err := uc.setBalances(ctx, res, br, addresses.token, rk); err != nil {
+			return nil,
+				fmt.Errorf("StatsOfChangingUseCase - getAddressWithBiggestChange - setBalances: %w", err)
+		}
+	}
+	// Transactions are added only on request, because they make response much bigger
+	if query.Explain && res.Address != "" {
+		res.Transactions = explainChange(addresses.addresses[res.Address], rk.metric)
 	}
 	// Returning result of finding address with biggest changing.
-	return uc.getMaxChanging(addresses, currentBlock, int64(countOfLastBlocks)), nil
+	return res, nil
+}
+
+// Get limit addresses with biggest changes in blocks described by query.
+func (uc *StatsOfChangingUseCase) GetTopChanges(
+	ctx context.Context,
+	query entity.ChangesQuery,
+	limit uint,
+) (*entity.TopChanges, error) {
+	if limit == 0 {
+		limit = uc.topLimit
+	}
+	// Limit is used for allocation, so it's checked before any work
+	if limit > uc.maxTopLimit {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - GetTopChanges: %w: %d is greater than %d",
+			entity.ErrInvalidLimit, limit, uc.maxTopLimit)
+	}
+
+	rk, err := uc.getRanking(query)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - GetTopChanges - uc.getRanking: %w", err)
+	}
+	// Getting range of blocks and map which store addresses and changes in it.
+	br, addresses, err := uc.getWindow(ctx, query)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - GetTopChanges - getWindow: %w", err)
+	}
+	// Summing changes of addresses of one entity if it's requested
+	if rk.groupBy == entity.GroupByEntity {
+		addresses, rk = uc.groupByEntity(addresses, rk)
+	}
+	// Relative metric needs balances of candidates before ranking
+	if rk.metric == entity.MetricRelative {
+		if err := uc.setRelativeScores(ctx, addresses, br, rk, int(limit)); err != nil {
+			return nil,
+				fmt.Errorf("StatsOfChangingUseCase - GetTopChanges - setRelativeScores: %w", err)
+		}
+	}
+
+	res := uc.getTopChanging(addresses, br, rk, int(limit))
+
+	if query.Balances || rk.metric == entity.MetricRelative {
+		if err := uc.setTopBalances(ctx, res, br, addresses.token, rk); err != nil {
+			return nil,
+				fmt.Errorf("StatsOfChangingUseCase - GetTopChanges - setTopBalances: %w", err)
+		}
+	}
+	// Returning ranked list of addresses with biggest changes.
+	return res, nil
+}
+
+// Get range of blocks described by query and changes in it.
+// Default window is followed in background, so it's got without requests.
+func (uc *StatsOfChangingUseCase) getWindow(
+	ctx context.Context,
+	query entity.ChangesQuery,
+) (*blockRange, *blockChanges, error) {
+	if w := uc.getFollowedWindow(query); w != nil {
+		br := *w.br
+
+		return &br, w.changes, nil
+	}
+	// Getting range of blocks in which changes are searched.
+	br, err := uc.getBlockRange(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("StatsOfChangingUseCase - getWindow - getBlockRange: %w", err)
+	}
+	// Getting changes of each block in range of blocks.
+	blocks, err := uc.getChangesByBlock(ctx, br, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("StatsOfChangingUseCase - getWindow - getChangesByBlock: %w", err)
+	}
+	// Getting time of window from its blocks.
+	if err := uc.setBlockRangeTimestamps(ctx, br, query, blocks); err != nil {
+		return nil, nil, fmt.Errorf("StatsOfChangingUseCase - getWindow - setBlockRangeTimestamps: %w", err)
+	}
+
+	return br, mergeChanges(blocks, uc.averageAddressCountInBlock*br.count), nil
+}
+
+// Merging changes of blocks into map which store addresses and changes in range of blocks.
+func mergeChanges(blocks []*blockChanges, size int) *blockChanges {
+	res := newBlockChanges(size)
+
+	for _, chs := range blocks {
+		res.merge(chs)
+	}
+
+	return res
+}
+
+// Get changes of ether or token balances in each block of range depending on query.
+func (uc *StatsOfChangingUseCase) getChangesByBlock(
+	ctx context.Context,
+	br *blockRange,
+	query entity.ChangesQuery,
+) ([]*blockChanges, error) {
+	if query.Token != "" {
+		return uc.getTokenChangesByBlock(ctx, br, query.Token)
+	}
+
+	return uc.getAddressChangesByBlock(ctx, br)
 }
 
-// Get map which store addresses and changes in last countOfLastBlocks blocks.
-func (uc *StatsOfChangingUseCase) getAddressChangeMap(
+// Get changes of addresses in each block of range.
+func (uc *StatsOfChangingUseCase) getAddressChangesByBlock(
 	ctx context.Context,
-	currentBlock *big.Int,
-	countOfLastBlocks int,
-) (map[string]*big.Int, error) {
+	br *blockRange,
+) ([]*blockChanges, error) {
+	blocks, err := uc.getBlocksChanges(ctx, br)
+	if err != nil {
+		return nil, err
+	}
+	// Cached blocks could be orphaned by reorg, so checking that window is one chain
+	if err := uc.verifyChain(ctx, br, blocks); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// Get changes of each block in range of blocks ordered by number.
+func (uc *StatsOfChangingUseCase) getBlocksChanges(
+	ctx context.Context,
+	br *blockRange,
+) ([]*blockChanges, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	var wg sync.WaitGroup
 
-	var errCh = make(chan error, countOfLastBlocks)
+	var errCh = make(chan error, br.count)
 
-	var mutex sync.Mutex
-
-	addresses := make(map[string]*big.Int, uc.averageAddressCountInBlock*countOfLastBlocks)
+	res := make([]*blockChanges, br.count)
 
 	pool := make(chan struct{}, uc.maxGoroutines)
 
-	wg.Add(countOfLastBlocks)
-
-	// Starting from oldest blocks for store earliest blocks
-	firstBlock := new(big.Int).Sub(currentBlock, big.NewInt(int64(countOfLastBlocks-1)))
+	wg.Add(br.count)
 
 	// Launching goroutines pool
-	// where we process addresses with changes to each block and
-	// calculate the total value in addresses map.
-	for i := 0; i < countOfLastBlocks; i++ {
+	// where we process addresses with changes to each block.
+	for i := 0; i < br.count; i++ {
 		pool <- struct{}{}
 
 		go func(offset int) {
 			defer wg.Done()
 			defer func() { <-pool }()
 
-			blockNumber := new(big.Int).Add(firstBlock, big.NewInt(int64(offset)))
+			blockNumber := new(big.Int).Add(br.first, big.NewInt(int64(offset)))
 
 			// Get addresses with changes by blockNumber.
 			// If we get error, sending it in errCh and cancelling context.
@@ -112,16 +296,8 @@ func (uc *StatsOfChangingUseCase) getAddressChangeMap(
 
 				return
 			}
-			// Adding new change into addresses.
-			mutex.Lock()
-			for addr, change := range chs {
-				if addresses[addr] == nil {
-					addresses[addr] = new(big.Int)
-				}
-
-				addresses[addr] = new(big.Int).Add(addresses[addr], change)
-			}
-			mutex.Unlock()
+			// Every goroutine writes only its own element.
+			res[offset] = chs
 		}(i)
 
 		// If some gouroutine is already ended with error, returning this error
@@ -142,81 +318,232 @@ func (uc *StatsOfChangingUseCase) getAddressChangeMap(
 		return nil, err
 	}
 
-	return addresses, nil
+	return res, nil
 }
 
 // Getting addresses with changes by number of block.
 func (uc *StatsOfChangingUseCase) getAddressWithChanges(
 	ctx context.Context,
 	blockNumber *big.Int,
-) (map[string]*big.Int, error) {
+) (*blockChanges, error) {
 	// Trying to get values from cache
 	if cachedResult, ok := uc.cache.Get(blockNumber.String()); ok {
-		res, ok := cachedResult.(map[string]*big.Int)
+		res, ok := cachedResult.(*blockChanges)
 		if ok {
 			return res, nil
 		}
 	}
 
-	chs := make(map[string]*big.Int, uc.averageAddressCountInBlock)
-
 	// Making request to web api
-	trs, err := uc.webAPI.GetTransactionsByBlockNumber(ctx, blockNumber)
+	block, err := uc.webAPI.GetBlockByNumber(ctx, blockNumber)
 	if err != nil {
 		return nil,
-			fmt.Errorf("StatsOfChangingUseCase - getAddressWithChanges - uc.webAPI.GetTransactionsByBlockNumber: %w", err)
+			fmt.Errorf("StatsOfChangingUseCase - getAddressWithChanges - uc.webAPI.GetBlockByNumber: %w", err)
 	}
 
-	// Calculating amount that the sender spent and receiver got
-	for _, t := range trs {
-		totalGas := new(big.Int).Mul(t.Gas, t.GasPrice)
-		totalFrom := new(big.Int).Add(t.Value, totalGas)
+	chs := newBlockChanges(uc.averageAddressCountInBlock)
+	chs.hash = block.Hash
+	chs.parentHash = block.ParentHash
+	chs.timestamp = block.Timestamp
+
+	priorityFees := new(big.Int)
 
-		if chs[t.From] == nil {
-			chs[t.From] = new(big.Int)
+	// Calculating amount that the sender spent and receiver got.
+	// Failed transaction doesn't transfer value, but sender still pays fee.
+	for _, t := range block.Transactions {
+		fee := t.Fee()
+		burnedFee := t.BurnedFee(block.BaseFeePerGas)
+
+		chs.burnedFees.Add(chs.burnedFees, burnedFee)
+		priorityFees.Add(priorityFees, new(big.Int).Sub(fee, burnedFee))
+
+		chs.addTransaction(t.From)
+		chs.addFee(t.From, fee)
+
+		if t.Failed() {
+			chs.failedTransactions++
+			chs.addTxRef(t.From, &txRef{hash: t.Hash, block: blockNumber.Uint64(), value: new(big.Int), fee: fee})
+
+			continue
 		}
 
-		if chs[t.To] == nil {
-			chs[t.To] = new(big.Int)
+		chs.addSent(t.From, t.Value)
+		chs.addTxRef(t.From, &txRef{hash: t.Hash, block: blockNumber.Uint64(), value: t.Value, fee: fee})
+		// Without address of created contract nobody can be credited
+		if t.Recipient() == "" {
+			continue
 		}
 
-		chs[t.From] = new(big.Int).Sub(chs[t.From], totalFrom)
-		chs[t.To] = new(big.Int).Add(chs[t.To], t.Value)
+		if t.CreatesContract() {
+			chs.createdContracts[t.Recipient()] = struct{}{}
+		}
+
+		chs.addTransaction(t.Recipient())
+		chs.addReceived(t.Recipient(), t.Value)
+		chs.addTxRef(t.Recipient(), &txRef{
+			hash:       t.Hash,
+			block:      blockNumber.Uint64(),
+			value:      t.Value,
+			fee:        new(big.Int),
+			isRecieved: true,
+		})
+	}
+	// Fee recipient of block gets everything what wasn't burned, it's unknown if api didn't return miner
+	if priorityFees.Sign() != 0 && block.Miner != "" {
+		chs.addReceived(block.Miner, priorityFees)
+	}
+	// Transfers made by contracts inside of successful transactions
+	for _, it := range block.InternalTransfers {
+		chs.addTransfer(it.From, it.To, it.Value)
+	}
+	// Withdrawals from beacon chain only credit addresses
+	if uc.withdrawals {
+		for _, w := range block.Withdrawals {
+			chs.addReceived(w.Address, w.Amount)
+		}
+	}
+	// Block without hash can't be checked for reorganization, so it's requested again next time
+	if chs.hash != "" {
+		uc.cache.Add(blockNumber.String(), chs)
 	}
-	// Adding value in cache
-	uc.cache.Add(blockNumber.String(), chs)
 
 	return chs, nil
 }
 
-// Getting max change in map with addresses and them changing.
+// Getting max change by ranking in map with addresses and them changing.
 func (uc *StatsOfChangingUseCase) getMaxChanging(
-	addresses map[string]*big.Int,
-	currentBlock *big.Int,
-	countOfLastBlocks int64,
+	chs *blockChanges,
+	br *blockRange,
+	rk *ranking,
 ) *entity.BiggestChange {
 	maxChange := big.NewInt(0)
 	res := &entity.BiggestChange{
-		LastBlock:     int2hex(currentBlock),
-		CountOfBlocks: countOfLastBlocks,
+		FirstBlock:         int2hex(br.first),
+		LastBlock:          int2hex(br.last),
+		FirstBlockTime:     br.firstTimestamp,
+		LastBlockTime:      br.lastTimestamp,
+		Metric:             rk.metric,
+		CountOfBlocks:      int64(br.count),
+		FailedTransactions: chs.failedTransactions,
+		BurnedFees:         int2hex(chs.burnedFees),
+		Anchor:             br.anchor,
+		AnchorBlock:        int2hex(br.anchorBlock),
+		Token:              chs.token,
+		Filters:            rk.filters,
+	}
+	// Grouping is shown only if it isn't default
+	if rk.groupBy != entity.GroupByAddress {
+		res.GroupBy = rk.groupBy
 	}
 	// Comparing the current maxChange with current amount
-	for addr, amount := range addresses {
-		if new(big.Int).Abs(amount).Cmp(new(big.Int).Abs(maxChange)) > 0 {
+	for addr, change := range chs.addresses {
+		if !rk.allows(addr) {
+			continue
+		}
+
+		amount := rk.value(addr, change)
+		if amount == nil {
+			continue
+		}
+
+		if isBiggerChange(addr, amount, res.Address, maxChange) {
 			res.Address = addr
 			maxChange = amount
 		}
 	}
-	// If number is not positive IsRecieved will be false
-	if maxChange.Cmp(big.NewInt(0)) > 0 {
-		res.IsRecieved = true
+	// If net change is not positive IsRecieved will be false
+	if res.Address != "" {
+		res.IsRecieved = chs.addresses[res.Address].net().Sign() > 0
+		res.Label = rk.label(res.Address)
+		res.Entity, res.Addresses = rk.group(res.Address)
+		maxChange = rk.amount(res.Address, chs, maxChange)
 	}
+
+	res.IsNewContract = chs.isCreatedContract(res.Address)
 	// Amount will be unsigned
 	res.Amount = int2hex(new(big.Int).Abs(maxChange))
 
 	return res
 }
 
+// Getting limit biggest changes by ranking in map with addresses and them changing.
+func (uc *StatsOfChangingUseCase) getTopChanging(
+	chs *blockChanges,
+	br *blockRange,
+	rk *ranking,
+	limit int,
+) *entity.TopChanges {
+	res := &entity.TopChanges{
+		Changes:            make([]*entity.AddressChange, 0, min(limit, len(chs.addresses))),
+		FirstBlock:         int2hex(br.first),
+		LastBlock:          int2hex(br.last),
+		FirstBlockTime:     br.firstTimestamp,
+		LastBlockTime:      br.lastTimestamp,
+		Metric:             rk.metric,
+		CountOfBlocks:      int64(br.count),
+		FailedTransactions: chs.failedTransactions,
+		BurnedFees:         int2hex(chs.burnedFees),
+		Anchor:             br.anchor,
+		AnchorBlock:        int2hex(br.anchorBlock),
+		Token:              chs.token,
+		Filters:            rk.filters,
+	}
+	// Grouping is shown only if it isn't default
+	if rk.groupBy != entity.GroupByAddress {
+		res.GroupBy = rk.groupBy
+	}
+
+	addresses := make(map[string]*big.Int, len(chs.addresses))
+
+	// Addresses with zero value are not changed at all, so skipping them
+	ranked := make([]string, 0, len(chs.addresses))
+
+	for addr, change := range chs.addresses {
+		if !rk.allows(addr) {
+			continue
+		}
+
+		if amount := rk.value(addr, change); amount != nil && amount.Sign() != 0 {
+			addresses[addr] = amount
+			ranked = append(ranked, addr)
+		}
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		return isBiggerChange(ranked[i], addresses[ranked[i]], ranked[j], addresses[ranked[j]])
+	})
+
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	for _, addr := range ranked {
+		entityID, members := rk.group(addr)
+
+		res.Changes = append(res.Changes, &entity.AddressChange{
+			Address:       addr,
+			Label:         rk.label(addr),
+			Entity:        entityID,
+			Addresses:     members,
+			Amount:        int2hex(new(big.Int).Abs(rk.amount(addr, chs, addresses[addr]))),
+			IsRecieved:    chs.addresses[addr].net().Sign() > 0,
+			IsNewContract: chs.isCreatedContract(addr),
+		})
+	}
+
+	return res
+}
+
+// Checking that change of address a is bigger than change of address b.
+// Ties are broken by address, so the order doesn't depend on map iteration.
+func isBiggerChange(addrA string, amountA *big.Int, addrB string, amountB *big.Int) bool {
+	if cmp := amountA.CmpAbs(amountB); cmp != 0 {
+		return cmp > 0
+	}
+
+	return addrA < addrB
+}
+
 func int2hex(i *big.Int) string {
 	return fmt.Sprintf("%#x", i)
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/egor-denisov/biggest-change/internal/entity"
 	mock "github.com/egor-denisov/biggest-change/internal/usecase/mocks"
@@ -15,6 +17,8 @@ import (
 
 var errSomethingWentWrong = errors.New("something went wrong")
 
+const testToken = "0xdac17f958d2ee523a2206206994597c13d831ec7"
+
 func Test_GetAddressWithBiggestChange(t *testing.T) {
 	for _, test := range testsGetAddressWithBiggestChange {
 		t.Run(test.name, func(t *testing.T) {
@@ -23,10 +27,10 @@ func Test_GetAddressWithBiggestChange(t *testing.T) {
 			defer c.Finish()
 
 			service := mock.NewMockStatsOfChangingWebAPI(c)
-			test.mockBehavior(service, test.countOfBlocks)
+			test.mockBehavior(service, test.query.CountOfBlocks)
 
 			// Call function
-			biggestChange, err := New(service).GetAddressWithBiggestChange(context.Background(), test.countOfBlocks)
+			biggestChange, err := New(service, test.options...).GetAddressWithBiggestChange(context.Background(), test.query)
 
 			assert.Equal(t, test.expectedResult, biggestChange)
 			assert.Equal(t, errors.Is(err, test.expectedError), true)
@@ -34,12 +38,36 @@ func Test_GetAddressWithBiggestChange(t *testing.T) {
 	}
 }
 
+func uint64Ptr(i uint64) *uint64 {
+	return &i
+}
+
 type mockBehavior func(m *mock.MockStatsOfChangingWebAPI, countOfBlocks uint)
 
+// Mocking chain with head 10, where block n is mined at 100 + 12*n and transfers 100 from 0x1 to 0x2.
+// Time window is searched by headers, so only blocks of window are requested.
+func mockTimedBlocks(m *mock.MockStatsOfChangingWebAPI) {
+	mockTimedHeaders(m)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, blockNumber *big.Int) (*entity.Block, error) {
+			return &entity.Block{
+				Timestamp: 100 + 12*blockNumber.Uint64(),
+				Transactions: []*entity.Transaction{
+					{From: "0x1", To: "0x2", Value: big.NewInt(100), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+				},
+			}, nil
+		}).AnyTimes()
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
 var testsGetAddressWithBiggestChange = []struct {
 	name           string
 	mockBehavior   mockBehavior
-	countOfBlocks  uint
+	options        []Option
+	query          entity.ChangesQuery
 	expectedResult *entity.BiggestChange
 	expectedError  error
 }{
@@ -47,18 +75,23 @@ var testsGetAddressWithBiggestChange = []struct {
 		name: "Success - Single Block",
 		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
 			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
-			m.EXPECT().GetTransactionsByBlockNumber(gomock.Any(), big.NewInt(200)).Return([]*entity.Transaction{
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{Transactions: []*entity.Transaction{
 				{From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(50), GasPrice: big.NewInt(2)},
 				{From: "0x3", To: "0x4", Value: big.NewInt(100), Gas: big.NewInt(20), GasPrice: big.NewInt(2)},
-			}, nil)
+			}}, nil)
 		},
-		countOfBlocks: 1,
+		query: entity.ChangesQuery{CountOfBlocks: 1},
 		expectedResult: &entity.BiggestChange{
 			Address:       "0x1",
 			Amount:        "0x258",
 			IsRecieved:    false,
+			FirstBlock:    "0xc8",
 			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
 			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
 		},
 		expectedError: nil,
 	},
@@ -68,19 +101,24 @@ var testsGetAddressWithBiggestChange = []struct {
 			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
 			for i := uint(0); i < countOfBlocks; i++ {
 				blockNum := big.NewInt(200 - int64(i))
-				m.EXPECT().GetTransactionsByBlockNumber(gomock.Any(), blockNum).Return([]*entity.Transaction{
+				m.EXPECT().GetBlockByNumber(gomock.Any(), blockNum).Return(&entity.Block{Transactions: []*entity.Transaction{
 					{From: "0x1", To: "0x3", Value: big.NewInt(300 * int64(i+1)), Gas: big.NewInt(30), GasPrice: big.NewInt(3)},
 					{From: "0x4", To: "0x3", Value: big.NewInt(100 * int64(i+1)), Gas: big.NewInt(10), GasPrice: big.NewInt(3)},
-				}, nil)
+				}}, nil)
 			}
 		},
-		countOfBlocks: 3,
+		query: entity.ChangesQuery{CountOfBlocks: 3},
 		expectedResult: &entity.BiggestChange{
 			Address:       "0x3",
 			Amount:        "0x960",
 			IsRecieved:    true,
+			FirstBlock:    "0xc6",
 			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
 			CountOfBlocks: 3,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
 		},
 		expectedError: nil,
 	},
@@ -90,24 +128,670 @@ var testsGetAddressWithBiggestChange = []struct {
 			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
 			for i := uint(0); i < _defaultCountOfBlocks; i++ {
 				blockNum := big.NewInt(200 - int64(i))
-				m.EXPECT().GetTransactionsByBlockNumber(gomock.Any(), blockNum).Return(nil, nil)
+				m.EXPECT().GetBlockByNumber(gomock.Any(), blockNum).Return(&entity.Block{}, nil)
 			}
 		},
-		countOfBlocks: 0,
+		query: entity.ChangesQuery{},
 		expectedResult: &entity.BiggestChange{
 			Amount:        "0x0",
 			IsRecieved:    false,
+			FirstBlock:    "0x65",
 			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
 			CountOfBlocks: int64(_defaultCountOfBlocks),
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Fee From Receipt",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{Transactions: []*entity.Transaction{
+				{
+					From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(500), GasPrice: big.NewInt(3),
+					GasUsed: big.NewInt(50), EffectiveGasPrice: big.NewInt(2), Status: 1,
+				},
+			}}, nil)
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 1},
+		expectedResult: &entity.BiggestChange{
+			Address:       "0x1",
+			Amount:        "0x258",
+			IsRecieved:    false,
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Failed Transaction Pays Only Fee",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{Transactions: []*entity.Transaction{
+				{
+					From: "0x1", To: "0x2", Value: big.NewInt(5000), Gas: big.NewInt(500), GasPrice: big.NewInt(3),
+					GasUsed: big.NewInt(50), EffectiveGasPrice: big.NewInt(2), Status: 0,
+				},
+				{
+					From: "0x3", To: "0x4", Value: big.NewInt(300), Gas: big.NewInt(500), GasPrice: big.NewInt(3),
+					GasUsed: big.NewInt(50), EffectiveGasPrice: big.NewInt(2), Status: 1,
+				},
+			}}, nil)
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 1},
+		expectedResult: &entity.BiggestChange{
+			Address:            "0x3",
+			Amount:             "0x190",
+			IsRecieved:         false,
+			FirstBlock:         "0xc8",
+			LastBlock:          "0xc8",
+			Metric:             entity.MetricNet,
+			CountOfBlocks:      1,
+			FailedTransactions: 1,
+			BurnedFees:         "0x0",
+			Anchor:             "latest",
+			AnchorBlock:        "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Explain",
+		mockBehavior: mockExplainBlocks,
+		query:        entity.ChangesQuery{CountOfBlocks: 2, Explain: true},
+		expectedResult: &entity.BiggestChange{
+			Address:            "0x1",
+			Amount:             "0x168",
+			IsRecieved:         false,
+			FirstBlock:         "0xc7",
+			LastBlock:          "0xc8",
+			Metric:             entity.MetricNet,
+			CountOfBlocks:      2,
+			FailedTransactions: 1,
+			BurnedFees:         "0x0",
+			Anchor:             "latest",
+			AnchorBlock:        "0xc8",
+			Transactions: []*entity.ContributingTransaction{
+				{Hash: "0xa", Block: "0xc7", Value: "0x12c", Fee: "0x0", IsRecieved: false},
+				{Hash: "0xb", Block: "0xc7", Value: "0x64", Fee: "0x0", IsRecieved: false},
+				{Hash: "0xc", Block: "0xc8", Value: "0x32", Fee: "0x0", IsRecieved: true},
+				{Hash: "0xd", Block: "0xc8", Value: "0x0", Fee: "0xa", IsRecieved: false},
+			},
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Explain Inflow",
+		mockBehavior: mockExplainBlocks,
+		query:        entity.ChangesQuery{CountOfBlocks: 2, Metric: entity.MetricInflow, Explain: true},
+		expectedResult: &entity.BiggestChange{
+			Address:            "0x2",
+			Amount:             "0x12c",
+			IsRecieved:         true,
+			FirstBlock:         "0xc7",
+			LastBlock:          "0xc8",
+			Metric:             entity.MetricInflow,
+			CountOfBlocks:      2,
+			FailedTransactions: 1,
+			BurnedFees:         "0x0",
+			Anchor:             "latest",
+			AnchorBlock:        "0xc8",
+			Transactions: []*entity.ContributingTransaction{
+				{Hash: "0xa", Block: "0xc7", Value: "0x12c", Fee: "0x0", IsRecieved: true},
+			},
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Exclude",
+		mockBehavior: mockFilterBlock,
+		query:        entity.ChangesQuery{CountOfBlocks: 1, Exclude: []string{strings.ToUpper(testAddressA)}},
+		expectedResult: &entity.BiggestChange{
+			Address:       testAddressB,
+			Amount:        "0x12c",
+			IsRecieved:    true,
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+			Filters:       &entity.AddressFilters{Exclude: []string{testAddressA}},
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Include",
+		mockBehavior: mockFilterBlock,
+		query:        entity.ChangesQuery{CountOfBlocks: 1, Include: []string{testAddressC, testAddressB, testAddressC}},
+		expectedResult: &entity.BiggestChange{
+			Address:       testAddressB,
+			Amount:        "0x12c",
+			IsRecieved:    true,
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+			Filters:       &entity.AddressFilters{Include: []string{testAddressB, testAddressC}},
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Excluded By Config",
+		mockBehavior: mockFilterBlock,
+		options:      []Option{Exclude([]string{testAddressA, strings.ToUpper(testAddressB)})},
+		query:        entity.ChangesQuery{CountOfBlocks: 1},
+		expectedResult: &entity.BiggestChange{
+			Address:       testAddressC,
+			Amount:        "0x64",
+			IsRecieved:    true,
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+			Filters:       &entity.AddressFilters{ExcludedByConfig: 2},
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Label",
+		mockBehavior: mockFilterBlock,
+		options:      []Option{Labels(testLabels{testAddressA: {Name: "Binance 14", Category: "exchange"}})},
+		query:        entity.ChangesQuery{CountOfBlocks: 1},
+		expectedResult: &entity.BiggestChange{
+			Address:       testAddressA,
+			Label:         &entity.Label{Name: "Binance 14", Category: "exchange"},
+			Amount:        "0x190",
+			IsRecieved:    false,
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Exclude Category",
+		mockBehavior: mockFilterBlock,
+		options:      []Option{Labels(testLabels{testAddressA: {Name: "Binance 14", Category: "exchange"}})},
+		query:        entity.ChangesQuery{CountOfBlocks: 1, ExcludeCategories: []string{" Exchange"}},
+		expectedResult: &entity.BiggestChange{
+			Address:       testAddressB,
+			Amount:        "0x12c",
+			IsRecieved:    true,
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+			Filters:       &entity.AddressFilters{ExcludeCategories: []string{"exchange"}},
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Group By Entity",
+		mockBehavior: mockFilterBlock,
+		options:      []Option{Clusters(testClusters{testAddressA: "binance", testAddressB: "binance"})},
+		query: entity.ChangesQuery{
+			CountOfBlocks: 1, GroupBy: entity.GroupByEntity, Exclude: []string{testAddressC},
+		},
+		expectedResult: &entity.BiggestChange{
+			Address:       "binance",
+			Entity:        "binance",
+			Addresses:     []string{testAddressA, testAddressB},
+			Amount:        "0x64",
+			IsRecieved:    false,
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			GroupBy:       entity.GroupByEntity,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+			Filters:       &entity.AddressFilters{Exclude: []string{testAddressC}},
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Relative",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, c uint) {
+			mockFilterBlock(m, c)
+			mockBalances(m, map[string][2]int64{testAddressA: {1000, 600}, testAddressB: {50, 350}, testAddressC: {1000, 1100}})
+		},
+		options: []Option{RelativeMinBalance(1e-16)},
+		query:   entity.ChangesQuery{CountOfBlocks: 1, Metric: entity.MetricRelative},
+		expectedResult: &entity.BiggestChange{
+			Address:       testAddressB,
+			Amount:        "0x12c",
+			StartBalance:  "0x32",
+			EndBalance:    "0x15e",
+			PercentChange: percent(600),
+			IsRecieved:    true,
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricRelative,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Error - Getting balance",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, c uint) {
+			mockFilterBlock(m, c)
+			m.EXPECT().GetBalance(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errSomethingWentWrong).MinTimes(1)
+		},
+		query:          entity.ChangesQuery{CountOfBlocks: 1, Balances: true},
+		expectedResult: nil,
+		expectedError:  errSomethingWentWrong,
+	},
+	{
+		name:           "Error - Group By Entity With Gross Metric",
+		mockBehavior:   func(_ *mock.MockStatsOfChangingWebAPI, _ uint) {},
+		query:          entity.ChangesQuery{CountOfBlocks: 1, GroupBy: entity.GroupByEntity, Metric: entity.MetricGross},
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidGroupBy,
+	},
+	{
+		name:           "Error - Invalid Include Address",
+		mockBehavior:   func(_ *mock.MockStatsOfChangingWebAPI, _ uint) {},
+		query:          entity.ChangesQuery{CountOfBlocks: 1, Include: []string{"0x123"}},
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidAddress,
+	},
+	{
+		name: "Success - Withdrawals",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{
+				Transactions: []*entity.Transaction{
+					{From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+				},
+				Withdrawals: []*entity.Withdrawal{
+					{Address: "0x5", Amount: big.NewInt(400)},
+					{Address: "0x5", Amount: big.NewInt(300)},
+				},
+			}, nil)
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 1},
+		expectedResult: &entity.BiggestChange{
+			Address:       "0x5",
+			Amount:        "0x2bc",
+			IsRecieved:    true,
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Withdrawals Disabled",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{
+				Transactions: []*entity.Transaction{
+					{From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+				},
+				Withdrawals: []*entity.Withdrawal{
+					{Address: "0x5", Amount: big.NewInt(400)},
+					{Address: "0x5", Amount: big.NewInt(300)},
+				},
+			}, nil)
+		},
+		options: []Option{Withdrawals(false)},
+		query:   entity.ChangesQuery{CountOfBlocks: 1},
+		expectedResult: &entity.BiggestChange{
+			Address:       "0x1",
+			Amount:        "0x1f4",
+			IsRecieved:    false,
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Internal Transfers",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{
+				Transactions: []*entity.Transaction{
+					{From: "0x1", To: "0xc", Value: big.NewInt(500), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+				},
+				InternalTransfers: []*entity.InternalTransfer{
+					{From: "0xc", To: "0x7", Value: big.NewInt(300)},
+					{From: "0xc", To: "0x7", Value: big.NewInt(300)},
+				},
+			}, nil)
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 1},
+		expectedResult: &entity.BiggestChange{
+			Address:       "0x7",
+			Amount:        "0x258",
+			IsRecieved:    true,
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Block Range",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(150)).Return(&entity.Block{Transactions: []*entity.Transaction{
+				{From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(50), GasPrice: big.NewInt(2)},
+			}}, nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(151)).Return(&entity.Block{Transactions: []*entity.Transaction{
+				{From: "0x3", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(50), GasPrice: big.NewInt(2)},
+			}}, nil)
+		},
+		query: entity.ChangesQuery{FromBlock: uint64Ptr(150), ToBlock: uint64Ptr(151)},
+		expectedResult: &entity.BiggestChange{
+			Address:       "0x2",
+			Amount:        "0x3e8",
+			IsRecieved:    true,
+			FirstBlock:    "0x96",
+			LastBlock:     "0x97",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 2,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Only Last Block",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(149)).Return(&entity.Block{}, nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(150)).Return(&entity.Block{Transactions: []*entity.Transaction{
+				{From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(50), GasPrice: big.NewInt(2)},
+			}}, nil)
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 2, ToBlock: uint64Ptr(150)},
+		expectedResult: &entity.BiggestChange{
+			Address:       "0x1",
+			Amount:        "0x258",
+			IsRecieved:    false,
+			FirstBlock:    "0x95",
+			LastBlock:     "0x96",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 2,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Confirmation Depth",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(188)).Return(&entity.Block{Transactions: []*entity.Transaction{
+				{From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(50), GasPrice: big.NewInt(2)},
+			}}, nil)
+		},
+		options: []Option{ConfirmationDepth(12)},
+		query:   entity.ChangesQuery{CountOfBlocks: 1},
+		expectedResult: &entity.BiggestChange{
+			Address:       "0x1",
+			Amount:        "0x258",
+			IsRecieved:    false,
+			FirstBlock:    "0xbc",
+			LastBlock:     "0xbc",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xbc",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Finalized Anchor",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetBlockNumberByTag(context.Background(), entity.AnchorFinalized).Return(big.NewInt(150), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(150)).Return(&entity.Block{Transactions: []*entity.Transaction{
+				{From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(50), GasPrice: big.NewInt(2)},
+			}}, nil)
+		},
+		options: []Option{ConfirmationDepth(12)},
+		query:   entity.ChangesQuery{CountOfBlocks: 1, Anchor: entity.AnchorFinalized},
+		expectedResult: &entity.BiggestChange{
+			Address:       "0x1",
+			Amount:        "0x258",
+			IsRecieved:    false,
+			FirstBlock:    "0x96",
+			LastBlock:     "0x96",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "finalized",
+			AnchorBlock:   "0x96",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Error - To Block After Safe Block",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetBlockNumberByTag(context.Background(), entity.AnchorSafe).Return(big.NewInt(150), nil)
+		},
+		options:        []Option{Anchor(entity.AnchorSafe)},
+		query:          entity.ChangesQuery{ToBlock: uint64Ptr(151)},
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidBlockRange,
+	},
+	{
+		name:           "Error - Invalid Anchor",
+		mockBehavior:   func(_ *mock.MockStatsOfChangingWebAPI, _ uint) {},
+		query:          entity.ChangesQuery{Anchor: "pending"},
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidAnchor,
+	},
+	{
+		name:         "Success - Duration",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) { mockTimedBlocks(m) },
+		query:        entity.ChangesQuery{Duration: 30 * time.Second},
+		expectedResult: &entity.BiggestChange{
+			Address:        "0x1",
+			Amount:         "0x12c",
+			IsRecieved:     false,
+			FirstBlock:     "0x8",
+			LastBlock:      "0xa",
+			FirstBlockTime: 196,
+			LastBlockTime:  220,
+			Metric:         entity.MetricNet,
+			CountOfBlocks:  3,
+			BurnedFees:     "0x0",
+			Anchor:         "latest",
+			AnchorBlock:    "0xa",
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Since And Duration",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) { mockTimedBlocks(m) },
+		query:        entity.ChangesQuery{Since: timePtr(time.Unix(150, 0)), Duration: 30 * time.Second},
+		expectedResult: &entity.BiggestChange{
+			Address:        "0x1",
+			Amount:         "0xc8",
+			IsRecieved:     false,
+			FirstBlock:     "0x5",
+			LastBlock:      "0x6",
+			FirstBlockTime: 160,
+			LastBlockTime:  172,
+			Metric:         entity.MetricNet,
+			CountOfBlocks:  2,
+			BurnedFees:     "0x0",
+			Anchor:         "latest",
+			AnchorBlock:    "0xa",
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Since Genesis",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) { mockTimedBlocks(m) },
+		query:        entity.ChangesQuery{Since: timePtr(time.Unix(0, 0))},
+		expectedResult: &entity.BiggestChange{
+			Address:        "0x1",
+			Amount:         "0x44c",
+			IsRecieved:     false,
+			FirstBlock:     "0x0",
+			LastBlock:      "0xa",
+			FirstBlockTime: 100,
+			LastBlockTime:  220,
+			Metric:         entity.MetricNet,
+			CountOfBlocks:  11,
+			BurnedFees:     "0x0",
+			Anchor:         "latest",
+			AnchorBlock:    "0xa",
+		},
+		expectedError: nil,
+	},
+	{
+		name:           "Error - Time Window Without Blocks",
+		mockBehavior:   func(m *mock.MockStatsOfChangingWebAPI, _ uint) { mockTimedBlocks(m) },
+		query:          entity.ChangesQuery{Since: timePtr(time.Unix(161, 0)), Duration: 5 * time.Second},
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidBlockRange,
+	},
+	{
+		name: "Error - Since Before Unix Epoch",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(10), nil)
+		},
+		query:          entity.ChangesQuery{Since: timePtr(time.Unix(-1, 0))},
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidBlockRange,
+	},
+	{
+		name: "Error - Block Range Longer Than Maximum",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+		},
+		options:        []Option{MaxCountOfBlocks(100)},
+		query:          entity.ChangesQuery{FromBlock: uint64Ptr(0), ToBlock: uint64Ptr(100)},
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidBlockRange,
+	},
+	{
+		name: "Error - Time Mixed With Block Bounds",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(10), nil)
+		},
+		query:          entity.ChangesQuery{FromBlock: uint64Ptr(5), Duration: time.Minute},
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidBlockRange,
+	},
+	{
+		name: "Success - Orphaned Block Is Refetched",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			// Block 150 was replaced by reorg after it had been fetched
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(150)).Return(&entity.Block{
+				Hash: "0xa",
+				Transactions: []*entity.Transaction{
+					{From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(50), GasPrice: big.NewInt(2)},
+				},
+			}, nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(151)).Return(&entity.Block{
+				Hash: "0xc", ParentHash: "0xb",
+			}, nil).Times(2)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(150)).Return(&entity.Block{
+				Hash: "0xb",
+				Transactions: []*entity.Transaction{
+					{From: "0x3", To: "0x4", Value: big.NewInt(700), Gas: big.NewInt(50), GasPrice: big.NewInt(2)},
+				},
+			}, nil)
+		},
+		query: entity.ChangesQuery{FromBlock: uint64Ptr(150), ToBlock: uint64Ptr(151)},
+		expectedResult: &entity.BiggestChange{
+			Address:       "0x3",
+			Amount:        "0x320",
+			IsRecieved:    false,
+			FirstBlock:    "0x96",
+			LastBlock:     "0x97",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 2,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
 		},
 		expectedError: nil,
 	},
+	{
+		name: "Error - Chain Is Not Linked",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(150)).Return(&entity.Block{Hash: "0xa"}, nil).
+				Times(_maxReorgRetries + 1)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(151)).Return(&entity.Block{ParentHash: "0xb"}, nil).
+				Times(_maxReorgRetries + 1)
+		},
+		query:          entity.ChangesQuery{FromBlock: uint64Ptr(150), ToBlock: uint64Ptr(151)},
+		expectedResult: nil,
+		expectedError:  entity.ErrChainReorganized,
+	},
+	{
+		name: "Error - From Block After To Block",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+		},
+		query:          entity.ChangesQuery{FromBlock: uint64Ptr(151), ToBlock: uint64Ptr(150)},
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidBlockRange,
+	},
+	{
+		name: "Error - To Block After Head",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+		},
+		query:          entity.ChangesQuery{FromBlock: uint64Ptr(150), ToBlock: uint64Ptr(201)},
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidBlockRange,
+	},
 	{
 		name: "Error - Getting block number",
 		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
 			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(nil, errSomethingWentWrong)
 		},
-		countOfBlocks:  1,
+		query:          entity.ChangesQuery{CountOfBlocks: 1},
 		expectedResult: nil,
 		expectedError:  errSomethingWentWrong,
 	},
@@ -115,10 +799,558 @@ var testsGetAddressWithBiggestChange = []struct {
 		name: "Error - Getting change map",
 		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
 			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
-			m.EXPECT().GetTransactionsByBlockNumber(gomock.Any(), big.NewInt(200)).Return(nil, errSomethingWentWrong)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(nil, errSomethingWentWrong)
 		},
-		countOfBlocks:  1,
+		query:          entity.ChangesQuery{CountOfBlocks: 1},
 		expectedResult: nil,
 		expectedError:  errSomethingWentWrong,
 	},
 }
+
+func Test_getAddressWithChanges_Cache(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	service := mock.NewMockStatsOfChangingWebAPI(c)
+	// Block without hash is requested every time, block with hash is got from cache
+	service.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{}, nil).Times(2)
+	service.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(201)).Return(&entity.Block{Hash: "0xh"}, nil).Times(1)
+
+	uc := New(service)
+
+	for i := 0; i < 2; i++ {
+		for _, n := range []int64{200, 201} {
+			if _, err := uc.getAddressWithChanges(context.Background(), big.NewInt(n)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func Test_GetTopChanges(t *testing.T) {
+	for _, test := range testsGetTopChanges {
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock.NewMockStatsOfChangingWebAPI(c)
+			test.mockBehavior(service, test.query.CountOfBlocks)
+
+			// Call function
+			topChanges, err := New(service, test.options...).GetTopChanges(context.Background(), test.query, test.limit)
+
+			assert.Equal(t, test.expectedResult, topChanges)
+			assert.Equal(t, errors.Is(err, test.expectedError), true)
+		})
+	}
+}
+
+var testsGetTopChanges = []struct {
+	name           string
+	mockBehavior   mockBehavior
+	options        []Option
+	query          entity.ChangesQuery
+	limit          uint
+	expectedResult *entity.TopChanges
+	expectedError  error
+}{
+	{
+		name: "Success - Ranked with ties broken by address",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{Transactions: []*entity.Transaction{
+				{From: "0x5", To: "0x2", Value: big.NewInt(100), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+				{From: "0x3", To: "0x4", Value: big.NewInt(300), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+				{From: "0x6", To: "0x6", Value: big.NewInt(700), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+			}}, nil)
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 1},
+		limit: 3,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{Address: "0x3", Amount: "0x12c", IsRecieved: false},
+				{Address: "0x4", Amount: "0x12c", IsRecieved: true},
+				{Address: "0x2", Amount: "0x64", IsRecieved: true},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Fees without fee recipient",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{
+				BaseFeePerGas: big.NewInt(1),
+				Transactions: []*entity.Transaction{
+					{From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(50), GasPrice: big.NewInt(2)},
+				},
+			}, nil)
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 1},
+		limit: 0,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{Address: "0x1", Amount: "0x258", IsRecieved: false},
+				{Address: "0x2", Amount: "0x1f4", IsRecieved: true},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x32",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Default limit with fee recipient",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{
+				Miner:         "0x9",
+				BaseFeePerGas: big.NewInt(1),
+				Transactions: []*entity.Transaction{
+					{From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(50), GasPrice: big.NewInt(2)},
+				},
+			}, nil)
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 1},
+		limit: 0,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{Address: "0x1", Amount: "0x258", IsRecieved: false},
+				{Address: "0x2", Amount: "0x1f4", IsRecieved: true},
+				{Address: "0x9", Amount: "0x32", IsRecieved: true},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x32",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Contract Creation",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{
+				Transactions: []*entity.Transaction{
+					{From: "0x1", ContractAddress: "0xc", Value: big.NewInt(900), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+					{From: "0x2", Value: big.NewInt(500), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+				},
+			}, nil)
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 1},
+		limit: 5,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{Address: "0x1", Amount: "0x384", IsRecieved: false},
+				{Address: "0xc", Amount: "0x384", IsRecieved: true, IsNewContract: true},
+				{Address: "0x2", Amount: "0x1f4", IsRecieved: false},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Token Transfers",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetTokenDecimals(gomock.Any(), testToken).Return(uint8(6), nil)
+			m.EXPECT().GetTokenTransfers(gomock.Any(), testToken, big.NewInt(199), big.NewInt(200)).
+				Return([]*entity.TokenTransfer{
+					{Token: testToken, From: "0x1", To: "0x2", Value: big.NewInt(700), BlockNumber: big.NewInt(199)},
+					{Token: testToken, From: "0x2", To: "0x3", Value: big.NewInt(200), BlockNumber: big.NewInt(200)},
+				}, nil)
+			m.EXPECT().GetBlockHeader(gomock.Any(), big.NewInt(199)).Return(&entity.BlockHeader{Timestamp: 1000}, nil)
+			m.EXPECT().GetBlockHeader(gomock.Any(), big.NewInt(200)).Return(&entity.BlockHeader{Timestamp: 1012}, nil)
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 2, Token: "0x" + strings.ToUpper(testToken[2:])},
+		limit: 2,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{Address: "0x1", Amount: "0x2bc", IsRecieved: false},
+				{Address: "0x2", Amount: "0x1f4", IsRecieved: true},
+			},
+			FirstBlock:     "0xc7",
+			LastBlock:      "0xc8",
+			FirstBlockTime: 1000,
+			LastBlockTime:  1012,
+			Metric:         entity.MetricNet,
+			CountOfBlocks:  2,
+			BurnedFees:     "0x0",
+			Anchor:         "latest",
+			AnchorBlock:    "0xc8",
+			Token:          &entity.Token{Address: testToken, Decimals: 6},
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Inflow Metric",
+		mockBehavior: mockFlowsBlock,
+		query:        entity.ChangesQuery{CountOfBlocks: 1, Metric: entity.MetricInflow},
+		limit:        5,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{Address: "0x2", Amount: "0x1f4", IsRecieved: true},
+				{Address: "0x3", Amount: "0xc8", IsRecieved: true},
+				{Address: "0x9", Amount: "0x32", IsRecieved: true},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricInflow,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x32",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Outflow Metric",
+		mockBehavior: mockFlowsBlock,
+		query:        entity.ChangesQuery{CountOfBlocks: 1, Metric: entity.MetricOutflow},
+		limit:        5,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{Address: "0x1", Amount: "0x258", IsRecieved: false},
+				{Address: "0x2", Amount: "0xc8", IsRecieved: true},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricOutflow,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x32",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Gross Metric",
+		mockBehavior: mockFlowsBlock,
+		query:        entity.ChangesQuery{CountOfBlocks: 1, Metric: entity.MetricGross},
+		limit:        5,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{Address: "0x2", Amount: "0x2bc", IsRecieved: true},
+				{Address: "0x1", Amount: "0x258", IsRecieved: false},
+				{Address: "0x3", Amount: "0xc8", IsRecieved: true},
+				{Address: "0x9", Amount: "0x32", IsRecieved: true},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricGross,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x32",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Tx Count Metric",
+		mockBehavior: mockFlowsBlock,
+		query:        entity.ChangesQuery{CountOfBlocks: 1, Metric: entity.MetricTxCount},
+		limit:        3,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{Address: "0x2", Amount: "0x2", IsRecieved: true},
+				{Address: "0x1", Amount: "0x1", IsRecieved: false},
+				{Address: "0x3", Amount: "0x1", IsRecieved: true},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricTxCount,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x32",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Exclude",
+		mockBehavior: mockFilterBlock,
+		query:        entity.ChangesQuery{CountOfBlocks: 1, Exclude: []string{testAddressA}},
+		limit:        5,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{Address: testAddressB, Amount: "0x12c", IsRecieved: true},
+				{Address: testAddressC, Amount: "0x64", IsRecieved: true},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+			Filters:       &entity.AddressFilters{Exclude: []string{testAddressA}},
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Category",
+		mockBehavior: mockFilterBlock,
+		options: []Option{Labels(testLabels{
+			testAddressA: {Name: "Binance 14", Category: "exchange"},
+			testAddressB: {Name: "Arbitrum Bridge", Category: "bridge"},
+			testAddressC: {Name: "Coinbase 10", Category: "exchange"},
+		})},
+		query: entity.ChangesQuery{CountOfBlocks: 1, Categories: []string{"exchange"}},
+		limit: 5,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{
+					Address:    testAddressA,
+					Label:      &entity.Label{Name: "Binance 14", Category: "exchange"},
+					Amount:     "0x190",
+					IsRecieved: false,
+				},
+				{
+					Address:    testAddressC,
+					Label:      &entity.Label{Name: "Coinbase 10", Category: "exchange"},
+					Amount:     "0x64",
+					IsRecieved: true,
+				},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+			Filters:       &entity.AddressFilters{Categories: []string{"exchange"}},
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Group By Entity",
+		mockBehavior: mockFilterBlock,
+		options:      []Option{Clusters(testClusters{testAddressA: "binance", testAddressB: "binance"})},
+		query:        entity.ChangesQuery{CountOfBlocks: 1, GroupBy: entity.GroupByEntity},
+		limit:        5,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{Address: testAddressC, Amount: "0x64", IsRecieved: true},
+				{
+					Address:    "binance",
+					Entity:     "binance",
+					Addresses:  []string{testAddressA, testAddressB},
+					Amount:     "0x64",
+					IsRecieved: false,
+				},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			GroupBy:       entity.GroupByEntity,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name:         "Success - Group By Entity Named By Labeled Address",
+		mockBehavior: mockFilterBlock,
+		options: []Option{
+			Clusters(testClusters{testAddressA: testAddressA, testAddressB: testAddressA}),
+			Labels(testLabels{testAddressA: {Name: "Binance 14", Category: "exchange"}}),
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 1, GroupBy: entity.GroupByEntity},
+		limit: 5,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{
+					Address:    testAddressA,
+					Entity:     testAddressA,
+					Addresses:  []string{testAddressA, testAddressB},
+					Amount:     "0x64",
+					IsRecieved: false,
+				},
+				{Address: testAddressC, Amount: "0x64", IsRecieved: true},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			GroupBy:       entity.GroupByEntity,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name: "Success - Balances",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, c uint) {
+			mockFilterBlock(m, c)
+			mockBalances(m, map[string][2]int64{testAddressA: {1000, 600}, testAddressB: {0, 300}})
+		},
+		query: entity.ChangesQuery{CountOfBlocks: 1, Balances: true},
+		limit: 2,
+		expectedResult: &entity.TopChanges{
+			Changes: []*entity.AddressChange{
+				{
+					Address:       testAddressA,
+					Amount:        "0x190",
+					StartBalance:  "0x3e8",
+					EndBalance:    "0x258",
+					PercentChange: percent(-40),
+				},
+				// Percent change of empty address isn't defined
+				{Address: testAddressB, Amount: "0x12c", StartBalance: "0x0", EndBalance: "0x12c", IsRecieved: true},
+			},
+			FirstBlock:    "0xc8",
+			LastBlock:     "0xc8",
+			Metric:        entity.MetricNet,
+			CountOfBlocks: 1,
+			BurnedFees:    "0x0",
+			Anchor:        "latest",
+			AnchorBlock:   "0xc8",
+		},
+		expectedError: nil,
+	},
+	{
+		name:           "Error - Invalid Group By",
+		mockBehavior:   func(_ *mock.MockStatsOfChangingWebAPI, _ uint) {},
+		query:          entity.ChangesQuery{CountOfBlocks: 1, GroupBy: "owner"},
+		limit:          1,
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidGroupBy,
+	},
+	{
+		name:           "Error - Invalid Metric",
+		mockBehavior:   func(_ *mock.MockStatsOfChangingWebAPI, _ uint) {},
+		query:          entity.ChangesQuery{CountOfBlocks: 1, Metric: "volume"},
+		limit:          1,
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidMetric,
+	},
+	{
+		name: "Error - Invalid Token Address",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+		},
+		query:          entity.ChangesQuery{CountOfBlocks: 2, Token: "0x123"},
+		limit:          2,
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidAddress,
+	},
+	{
+		name: "Error - Getting block number",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(nil, errSomethingWentWrong)
+		},
+		query:          entity.ChangesQuery{CountOfBlocks: 1},
+		limit:          1,
+		expectedResult: nil,
+		expectedError:  errSomethingWentWrong,
+	},
+	{
+		name: "Error - Getting change map",
+		mockBehavior: func(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+			m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+			m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(nil, errSomethingWentWrong)
+		},
+		query:          entity.ChangesQuery{CountOfBlocks: 1},
+		limit:          1,
+		expectedResult: nil,
+		expectedError:  errSomethingWentWrong,
+	},
+	{
+		name:           "Error - Limit is greater than maximum",
+		mockBehavior:   func(_ *mock.MockStatsOfChangingWebAPI, _ uint) {},
+		options:        []Option{MaxTopLimit(50)},
+		query:          entity.ChangesQuery{CountOfBlocks: 1},
+		limit:          1 << 40,
+		expectedResult: nil,
+		expectedError:  entity.ErrInvalidLimit,
+	},
+}
+
+// Mocking block in which addresses have different inflow, outflow and count of transactions.
+func mockFlowsBlock(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+	m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{
+		Miner:         "0x9",
+		BaseFeePerGas: big.NewInt(1),
+		Transactions: []*entity.Transaction{
+			{From: "0x1", To: "0x2", Value: big.NewInt(500), Gas: big.NewInt(50), GasPrice: big.NewInt(2)},
+			{From: "0x2", To: "0x3", Value: big.NewInt(200), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+		},
+	}, nil)
+}
+
+// Mocking blocks 199 and 200, in which 0x1 sends, receives and pays fee for failed transaction.
+func mockExplainBlocks(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+	m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(199)).Return(&entity.Block{Transactions: []*entity.Transaction{
+		{Hash: "0xa", From: "0x1", To: "0x2", Value: big.NewInt(300), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+		{Hash: "0xb", From: "0x1", To: "0x3", Value: big.NewInt(100), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+	}}, nil)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{Transactions: []*entity.Transaction{
+		{Hash: "0xc", From: "0x2", To: "0x1", Value: big.NewInt(50), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+		{
+			Hash: "0xd", From: "0x1", To: "0x4", Value: big.NewInt(1000), Gas: big.NewInt(10), GasPrice: big.NewInt(1),
+			GasUsed: big.NewInt(10), EffectiveGasPrice: big.NewInt(1), Status: 0,
+		},
+	}}, nil)
+}
+
+// Mocking block in which testAddressA sends 300 to testAddressB and 100 to testAddressC.
+func mockFilterBlock(m *mock.MockStatsOfChangingWebAPI, _ uint) {
+	m.EXPECT().GetCurrentBlockNumber(context.Background()).Return(big.NewInt(200), nil)
+	m.EXPECT().GetBlockByNumber(gomock.Any(), big.NewInt(200)).Return(&entity.Block{Transactions: []*entity.Transaction{
+		{From: testAddressA, To: testAddressB, Value: big.NewInt(300), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+		{From: testAddressA, To: testAddressC, Value: big.NewInt(100), Gas: big.NewInt(0), GasPrice: big.NewInt(0)},
+	}}, nil)
+}
+
+// Mocking balances of addresses before and after block 200.
+func mockBalances(m *mock.MockStatsOfChangingWebAPI, balances map[string][2]int64) {
+	for addr, b := range balances {
+		m.EXPECT().GetBalance(gomock.Any(), addr, big.NewInt(199)).Return(big.NewInt(b[0]), nil)
+		m.EXPECT().GetBalance(gomock.Any(), addr, big.NewInt(200)).Return(big.NewInt(b[1]), nil)
+	}
+}
+
+func percent(v float64) *float64 {
+	return &v
+}
+
+// Labels of addresses by map.
+type testLabels map[string]*entity.Label
+
+func (l testLabels) Label(address string) *entity.Label {
+	return l[address]
+}
+
+// Entities of addresses by map.
+type testClusters map[string]string
+
+func (c testClusters) Entity(address string) string {
+	return c[address]
+}
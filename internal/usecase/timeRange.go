@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Resolving numbers of first and last blocks mined in time window described by query.
+// Blocks are searched by timestamps before anchor block, which are got from cached headers.
+func (uc *StatsOfChangingUseCase) getTimeBlockRange(
+	ctx context.Context,
+	query entity.ChangesQuery,
+	anchorBlock *big.Int,
+) (*big.Int, *big.Int, error) {
+	if query.FromBlock != nil || query.ToBlock != nil || query.Duration < 0 {
+		return nil, nil, fmt.Errorf("StatsOfChangingUseCase - getTimeBlockRange - mixed or negative bounds: %w",
+			entity.ErrInvalidBlockRange)
+	}
+	// Unix seconds are stored as unsigned, so time before 1970 would wrap around
+	if query.Since != nil && query.Since.Unix() < 0 {
+		return nil, nil, fmt.Errorf("StatsOfChangingUseCase - getTimeBlockRange - since before unix epoch: %w",
+			entity.ErrInvalidBlockRange)
+	}
+
+	anchorTime, err := uc.getHeaderTimestamp(ctx, anchorBlock.Int64())
+	if err != nil {
+		return nil, nil, fmt.Errorf("StatsOfChangingUseCase - getTimeBlockRange - getHeaderTimestamp: %w", err)
+	}
+
+	duration := uint64(query.Duration.Seconds())
+	// Bounds of window in unix seconds, both are inclusive
+	var from, to uint64
+
+	switch {
+	case query.Since != nil && duration != 0:
+		from = uint64(query.Since.Unix())
+		to = from + duration
+	case query.Since != nil:
+		from = uint64(query.Since.Unix())
+		to = anchorTime
+	case duration < anchorTime:
+		from = anchorTime - duration
+		to = anchorTime
+	default:
+		to = anchorTime
+	}
+
+	first, err := uc.searchBlockByTime(ctx, anchorBlock.Int64(), anchorTime, from, uc.getHeaderTimestamp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("StatsOfChangingUseCase - getTimeBlockRange - searchBlockByTime: %w", err)
+	}
+	// Last block of window is previous to first block mined after window
+	afterLast, err := uc.searchBlockByTime(ctx, anchorBlock.Int64(), anchorTime, to+1, uc.getHeaderTimestamp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("StatsOfChangingUseCase - getTimeBlockRange - searchBlockByTime: %w", err)
+	}
+
+	return big.NewInt(first), big.NewInt(afterLast - 1), nil
+}
+
+// Searching first block which is mined not before t in [0; head].
+// If there isn't such block, head + 1 is returned.
+// At first bounds are found by doubling of step back from head,
+// because time windows are usually near head, then binary search is used.
+// Timestamps of blocks are got by timestampOf.
+func (uc *StatsOfChangingUseCase) searchBlockByTime(
+	ctx context.Context,
+	head int64,
+	headTime, t uint64,
+	timestampOf func(ctx context.Context, blockNumber int64) (uint64, error),
+) (int64, error) {
+	if headTime < t {
+		return head + 1, nil
+	}
+	// Timestamp of lo is before t and timestamp of hi is not
+	lo, hi := head, head
+
+	for step := int64(1); ; step *= 2 {
+		lo = head - step
+		if lo < 0 {
+			lo = -1
+
+			break
+		}
+
+		ts, err := timestampOf(ctx, lo)
+		if err != nil {
+			return 0, err
+		}
+
+		if ts < t {
+			break
+		}
+
+		hi = lo
+	}
+
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+
+		ts, err := timestampOf(ctx, mid)
+		if err != nil {
+			return 0, err
+		}
+
+		if ts < t {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return hi, nil
+}
+
+// Setting timestamps of first and last blocks of range.
+// Ether changes are got from blocks which have timestamps already, headers are requested only for tokens.
+func (uc *StatsOfChangingUseCase) setBlockRangeTimestamps(
+	ctx context.Context,
+	br *blockRange,
+	query entity.ChangesQuery,
+	blocks []*blockChanges,
+) error {
+	if query.Token == "" && len(blocks) != 0 {
+		br.firstTimestamp = blocks[0].timestamp
+		br.lastTimestamp = blocks[len(blocks)-1].timestamp
+
+		return nil
+	}
+
+	var err error
+
+	if br.firstTimestamp, err = uc.getHeaderTimestamp(ctx, br.first.Int64()); err != nil {
+		return fmt.Errorf("StatsOfChangingUseCase - setBlockRangeTimestamps - getHeaderTimestamp: %w", err)
+	}
+
+	if br.lastTimestamp, err = uc.getHeaderTimestamp(ctx, br.last.Int64()); err != nil {
+		return fmt.Errorf("StatsOfChangingUseCase - setBlockRangeTimestamps - getHeaderTimestamp: %w", err)
+	}
+
+	return nil
+}
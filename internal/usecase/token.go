@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Get changes of token balances of addresses in each block of range.
+func (uc *StatsOfChangingUseCase) getTokenChangesByBlock(
+	ctx context.Context,
+	br *blockRange,
+	tokenAddress string,
+) ([]*blockChanges, error) {
+	token, err := uc.getToken(ctx, tokenAddress)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - getTokenChangesByBlock - getToken: %w", err)
+	}
+	// Transfer logs of whole range are got by one request
+	transfers, err := uc.webAPI.GetTokenTransfers(ctx, token.Address, br.first, br.last)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingUseCase - getTokenChangesByBlock - uc.webAPI.GetTokenTransfers: %w", err)
+	}
+
+	res := make([]*blockChanges, br.count)
+
+	for i := range res {
+		res[i] = newBlockChanges(0)
+		res[i].token = token
+	}
+
+	for _, t := range transfers {
+		offset := new(big.Int).Sub(t.BlockNumber, br.first).Int64()
+		// Logs outside of requested range can't be attributed to block of window
+		if offset < 0 || offset >= int64(br.count) {
+			continue
+		}
+
+		res[offset].addTransfer(t.From, t.To, t.Value)
+		res[offset].addTransaction(t.From)
+		res[offset].addTransaction(t.To)
+
+		block := t.BlockNumber.Uint64()
+		res[offset].addTxRef(t.From, &txRef{hash: t.TxHash, block: block, value: t.Value, fee: new(big.Int)})
+		res[offset].addTxRef(t.To, &txRef{hash: t.TxHash, block: block, value: t.Value, fee: new(big.Int), isRecieved: true})
+	}
+
+	return res, nil
+}
+
+// Getting token with decimals, which are cached because they never change.
+func (uc *StatsOfChangingUseCase) getToken(ctx context.Context, tokenAddress string) (*entity.Token, error) {
+	addr, err := entity.NormalizeAddress(tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - getToken - entity.NormalizeAddress: %w", err)
+	}
+
+	if decimals, ok := uc.tokenDecimals.Load(addr); ok {
+		return &entity.Token{Address: addr, Decimals: decimals.(uint8)}, nil //nolint:forcetypeassert // only uint8 is stored
+	}
+
+	decimals, err := uc.webAPI.GetTokenDecimals(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("StatsOfChangingUseCase - getToken - uc.webAPI.GetTokenDecimals: %w", err)
+	}
+
+	uc.tokenDecimals.Store(addr, decimals)
+
+	return &entity.Token{Address: addr, Decimals: decimals}, nil
+}
@@ -0,0 +1,117 @@
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// Selector of balanceOf(address).
+	_balanceOfSelector = "0x70a08231"
+	// Address argument of call is padded to 32 bytes.
+	_addressArgumentPadding = "000000000000000000000000"
+)
+
+// Building Request Body for eth_getBalance request.
+func getBalanceBuildRequestBody(address string, blockNumber *big.Int) (*bytes.Buffer, error) {
+	data := request{
+		JSONRPC: "2.0",
+		Method:  "eth_getBalance",
+		Params:  []interface{}{address, int2hex(blockNumber)},
+		ID:      "getblock.io",
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("getBalanceBuildRequestBody - json.Marshal: %w", err)
+	}
+
+	return bytes.NewBuffer(jsonData), nil
+}
+
+// Making request and getting balance of address in wei at the end of block.
+func (w *StatsOfChangingWebAPI) getBalance(
+	ctx context.Context,
+	address string,
+	blockNumber *big.Int,
+) (*big.Int, error) {
+	body, err := getBalanceBuildRequestBody(address, blockNumber)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBalance - getBalanceBuildRequestBody: %w", err)
+	}
+
+	response := callResponse{}
+
+	if err := w.retryRequest(ctx, body, &response); err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBalance - w.retryRequest: %w", err)
+	}
+
+	res, err := hex2int(response.Result)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBalance - hex2int: %w", err)
+	}
+
+	return res, nil
+}
+
+// Building Request Body for eth_call request of balanceOf(address).
+func tokenBalanceBuildRequestBody(token, address string, blockNumber *big.Int) (*bytes.Buffer, error) {
+	data := request{
+		JSONRPC: "2.0",
+		Method:  "eth_call",
+		Params: []interface{}{
+			map[string]string{
+				"to":   token,
+				"data": _balanceOfSelector + _addressArgumentPadding + strings.TrimPrefix(address, "0x"),
+			},
+			int2hex(blockNumber),
+		},
+		ID: "getblock.io",
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("tokenBalanceBuildRequestBody - json.Marshal: %w", err)
+	}
+
+	return bytes.NewBuffer(jsonData), nil
+}
+
+// Making request and getting balance of address in base units of token at the end of block.
+func (w *StatsOfChangingWebAPI) getTokenBalance(
+	ctx context.Context,
+	token, address string,
+	blockNumber *big.Int,
+) (*big.Int, error) {
+	body, err := tokenBalanceBuildRequestBody(token, address, blockNumber)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getTokenBalance - tokenBalanceBuildRequestBody: %w", err)
+	}
+
+	response := callResponse{}
+
+	if err := w.retryRequest(ctx, body, &response); err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getTokenBalance - w.retryRequest: %w", err)
+	}
+	// Token which didn't exist at block returns empty result
+	if response.Result == "0x" {
+		return new(big.Int), nil
+	}
+
+	res, err := hex2int(response.Result)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getTokenBalance - hex2int: %w", err)
+	}
+
+	return res, nil
+}
@@ -16,6 +16,7 @@ const (
 	_defaultTimeout            = 15 * time.Second
 	_defaultMaxRetries         = 5
 	_defaultTimeBetweenRetries = 500 * time.Millisecond
+	_defaultLogsBlockRange     = 1000
 )
 
 type StatsOfChangingWebAPI struct {
@@ -27,6 +28,8 @@ type StatsOfChangingWebAPI struct {
 	timeout            time.Duration
 	maxRetries         int
 	timeBetweenRetries time.Duration
+	tracing            bool
+	logsBlockRange     uint64
 }
 
 func New(url string, opts ...Option) *StatsOfChangingWebAPI {
@@ -42,6 +45,7 @@ func New(url string, opts ...Option) *StatsOfChangingWebAPI {
 		timeout:            _defaultTimeout,
 		maxRetries:         _defaultMaxRetries,
 		timeBetweenRetries: _defaultTimeBetweenRetries,
+		logsBlockRange:     _defaultLogsBlockRange,
 	}
 
 	for _, opt := range opts {
@@ -53,15 +57,39 @@ func New(url string, opts ...Option) *StatsOfChangingWebAPI {
 	return w
 }
 
-// Getting transactions by block number from getblock.io.
-func (w *StatsOfChangingWebAPI) GetTransactionsByBlockNumber(
+// Getting block with transactions by block number from getblock.io.
+func (w *StatsOfChangingWebAPI) GetBlockByNumber(
 	ctx context.Context,
 	blockNumber *big.Int,
-) ([]*entity.Transaction, error) {
+) (*entity.Block, error) {
 	ctx, cancel := context.WithTimeout(ctx, w.timeout)
 	defer cancel()
 
-	return w.getTransactionsByBlockNumber(ctx, blockNumber)
+	return w.getBlockByNumber(ctx, blockNumber)
+}
+
+// Getting number of block by tag (latest, safe, finalized) from getblock.io.
+func (w *StatsOfChangingWebAPI) GetBlockNumberByTag(ctx context.Context, tag string) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	header, err := w.getBlockHeader(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return header.Number, nil
+}
+
+// Getting header of block without transactions by block number from getblock.io.
+func (w *StatsOfChangingWebAPI) GetBlockHeader(
+	ctx context.Context,
+	blockNumber *big.Int,
+) (*entity.BlockHeader, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	return w.getBlockHeader(ctx, int2hex(blockNumber))
 }
 
 // Getting current block number from getblock.io.
@@ -71,3 +99,73 @@ func (w *StatsOfChangingWebAPI) GetCurrentBlockNumber(ctx context.Context) (*big
 
 	return w.getCurrentBlockNumber(ctx)
 }
+
+// Getting ERC-20 transfers of token in range of blocks from getblock.io.
+// Providers limit span and size of eth_getLogs, so range is requested by parts of logsBlockRange blocks.
+func (w *StatsOfChangingWebAPI) GetTokenTransfers(
+	ctx context.Context,
+	token string,
+	fromBlock, toBlock *big.Int,
+) ([]*entity.TokenTransfer, error) {
+	var res []*entity.TokenTransfer
+
+	for from := new(big.Int).Set(fromBlock); from.Cmp(toBlock) <= 0; {
+		to := new(big.Int).Set(toBlock)
+		if w.logsBlockRange != 0 {
+			end := new(big.Int).Add(from, new(big.Int).SetUint64(w.logsBlockRange-1))
+			if end.Cmp(to) < 0 {
+				to = end
+			}
+		}
+
+		transfers, err := w.getTokenTransfersWithTimeout(ctx, token, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, transfers...)
+		from = new(big.Int).Add(to, big.NewInt(1))
+	}
+
+	return res, nil
+}
+
+// Every part of range has its own timeout, so long ranges aren't limited by one timeout.
+func (w *StatsOfChangingWebAPI) getTokenTransfersWithTimeout(
+	ctx context.Context,
+	token string,
+	fromBlock, toBlock *big.Int,
+) ([]*entity.TokenTransfer, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	return w.getTokenTransfers(ctx, token, fromBlock, toBlock)
+}
+
+// Getting decimals of ERC-20 token from getblock.io.
+func (w *StatsOfChangingWebAPI) GetTokenDecimals(ctx context.Context, token string) (uint8, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	return w.getTokenDecimals(ctx, token)
+}
+
+// Getting balance of address at the end of block from getblock.io.
+func (w *StatsOfChangingWebAPI) GetBalance(ctx context.Context, address string, blockNumber *big.Int) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	return w.getBalance(ctx, address, blockNumber)
+}
+
+// Getting balance of address in ERC-20 token at the end of block from getblock.io.
+func (w *StatsOfChangingWebAPI) GetTokenBalance(
+	ctx context.Context,
+	token, address string,
+	blockNumber *big.Int,
+) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	return w.getTokenBalance(ctx, token, address, blockNumber)
+}
@@ -0,0 +1,477 @@
+package webapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+	"github.com/go-playground/assert"
+)
+
+// Fake JSON-RPC server which answers by method name.
+// Responses contain result or error member of json rpc response.
+func newFakeServer(t *testing.T, responses map[string]string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+
+		res, ok := responses[req.Method]
+		if !ok {
+			t.Errorf("unexpected method %s", req.Method)
+		}
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"getblock.io",` + res + `}`))
+	}))
+}
+
+// Comparing *big.Int by value, because its internal representation depends on how it was made.
+func toJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+// Getting web api which makes requests to fake server.
+func newTestWebAPI(url string, opts ...Option) *StatsOfChangingWebAPI {
+	w := New("https://go.getblock.io/test", opts...)
+	w.url = url
+
+	return w
+}
+
+const (
+	_testBlockResponse = `"result": {
+		"hash": "0xh2",
+		"parentHash": "0xh1",
+		"timestamp": "0x660a0000",
+		"miner": "0xm",
+		"baseFeePerGas": "0x1",
+		"transactions": [
+			{"hash": "0xt1", "from": "0x1", "to": "0xc", "value": "0x64", "gas": "0x100", "gasPrice": "0x3"},
+			{"hash": "0xt2", "from": "0x2", "to": null, "value": "0x0", "gas": "0x100", "gasPrice": "0x3"}
+		],
+		"withdrawals": [{"address": "0x5", "amount": "0x2"}]
+	}`
+	_testReceiptsResponse = `"result": [
+		{"transactionHash": "0xt1", "gasUsed": "0x10", "effectiveGasPrice": "0x2", "status": "0x1"},
+		{"transactionHash": "0xt2", "gasUsed": "0x20", "effectiveGasPrice": "0x2", "status": "0x1",
+			"contractAddress": "0xn"}
+	]`
+	_testTraceResponse = `"result": [
+		{"txHash": "0xt1", "result": {"type": "CALL", "from": "0x1", "to": "0xc", "value": "0x64", "calls": [
+			{"type": "CALL", "from": "0xc", "to": "0x7", "value": "0x30", "calls": [
+				{"type": "DELEGATECALL", "from": "0x7", "to": "0x8", "value": "0x30"}
+			]},
+			{"type": "CALL", "from": "0xc", "to": "0x9", "value": "0x10", "error": "execution reverted", "calls": [
+				{"type": "CALL", "from": "0x9", "to": "0xa", "value": "0x10"}
+			]},
+			{"type": "STATICCALL", "from": "0xc", "to": "0xb"}
+		]}},
+		{"txHash": "0xt2", "result": {"type": "CREATE", "from": "0x2", "to": "0xn", "value": "0x0"}}
+	]`
+)
+
+func Test_GetBlockByNumber(t *testing.T) {
+	for _, test := range testsGetBlockByNumber {
+		t.Run(test.name, func(t *testing.T) {
+			server := newFakeServer(t, test.responses)
+			defer server.Close()
+
+			w := newTestWebAPI(server.URL, test.options...)
+
+			block, err := w.GetBlockByNumber(context.Background(), big.NewInt(200))
+
+			assert.Equal(t, errors.Is(err, test.expectedError), true)
+			assert.Equal(t, toJSON(t, block), toJSON(t, test.expectedBlock))
+		})
+	}
+}
+
+var testsGetBlockByNumber = []struct {
+	name          string
+	options       []Option
+	responses     map[string]string
+	expectedBlock *entity.Block
+	expectedError error
+}{
+	{
+		name: "Without tracing",
+		responses: map[string]string{
+			"eth_getBlockByNumber": _testBlockResponse,
+			"eth_getBlockReceipts": _testReceiptsResponse,
+		},
+		expectedBlock: &entity.Block{
+			Number:        big.NewInt(200),
+			Hash:          "0xh2",
+			ParentHash:    "0xh1",
+			Timestamp:     1711931392,
+			Miner:         "0xm",
+			BaseFeePerGas: big.NewInt(1),
+			Transactions: []*entity.Transaction{
+				{
+					Hash: "0xt1", From: "0x1", To: "0xc", Value: big.NewInt(100), Gas: big.NewInt(256), GasPrice: big.NewInt(3),
+					GasUsed: big.NewInt(16), EffectiveGasPrice: big.NewInt(2), Status: 1,
+				},
+				{
+					Hash: "0xt2", From: "0x2", ContractAddress: "0xn", Value: big.NewInt(0),
+					Gas: big.NewInt(256), GasPrice: big.NewInt(3),
+					GasUsed: big.NewInt(32), EffectiveGasPrice: big.NewInt(2), Status: 1,
+				},
+			},
+			Withdrawals: []*entity.Withdrawal{{Address: "0x5", Amount: big.NewInt(2_000_000_000)}},
+		},
+	},
+	{
+		name:    "With tracing",
+		options: []Option{Tracing(true)},
+		responses: map[string]string{
+			"eth_getBlockByNumber":     _testBlockResponse,
+			"eth_getBlockReceipts":     _testReceiptsResponse,
+			"debug_traceBlockByNumber": _testTraceResponse,
+		},
+		expectedBlock: &entity.Block{
+			Number:        big.NewInt(200),
+			Hash:          "0xh2",
+			ParentHash:    "0xh1",
+			Timestamp:     1711931392,
+			Miner:         "0xm",
+			BaseFeePerGas: big.NewInt(1),
+			Transactions: []*entity.Transaction{
+				{
+					Hash: "0xt1", From: "0x1", To: "0xc", Value: big.NewInt(100), Gas: big.NewInt(256), GasPrice: big.NewInt(3),
+					GasUsed: big.NewInt(16), EffectiveGasPrice: big.NewInt(2), Status: 1,
+				},
+				{
+					Hash: "0xt2", From: "0x2", ContractAddress: "0xn", Value: big.NewInt(0),
+					Gas: big.NewInt(256), GasPrice: big.NewInt(3),
+					GasUsed: big.NewInt(32), EffectiveGasPrice: big.NewInt(2), Status: 1,
+				},
+			},
+			Withdrawals:       []*entity.Withdrawal{{Address: "0x5", Amount: big.NewInt(2_000_000_000)}},
+			InternalTransfers: []*entity.InternalTransfer{{From: "0xc", To: "0x7", Value: big.NewInt(48)}},
+		},
+	},
+	{
+		name: "Receipts without effective gas price",
+		responses: map[string]string{
+			"eth_getBlockByNumber": _testBlockResponse,
+			"eth_getBlockReceipts": `"result": [
+				{"transactionHash": "0xt1", "gasUsed": "0x10", "status": "0x1"},
+				{"transactionHash": "0xt2", "gasUsed": "0x20", "status": "0x1", "contractAddress": "0xn"}
+			]`,
+		},
+		expectedBlock: &entity.Block{
+			Number:        big.NewInt(200),
+			Hash:          "0xh2",
+			ParentHash:    "0xh1",
+			Timestamp:     1711931392,
+			Miner:         "0xm",
+			BaseFeePerGas: big.NewInt(1),
+			Transactions: []*entity.Transaction{
+				{
+					Hash: "0xt1", From: "0x1", To: "0xc", Value: big.NewInt(100), Gas: big.NewInt(256), GasPrice: big.NewInt(3),
+					GasUsed: big.NewInt(16), EffectiveGasPrice: big.NewInt(3), Status: 1,
+				},
+				{
+					Hash: "0xt2", From: "0x2", ContractAddress: "0xn", Value: big.NewInt(0),
+					Gas: big.NewInt(256), GasPrice: big.NewInt(3),
+					GasUsed: big.NewInt(32), EffectiveGasPrice: big.NewInt(3), Status: 1,
+				},
+			},
+			Withdrawals: []*entity.Withdrawal{{Address: "0x5", Amount: big.NewInt(2_000_000_000)}},
+		},
+	},
+	{
+		name: "Pre-Byzantium receipts without status",
+		responses: map[string]string{
+			"eth_getBlockByNumber": _testBlockResponse,
+			"eth_getBlockReceipts": `"result": [
+				{"transactionHash": "0xt1", "gasUsed": "0x10", "effectiveGasPrice": "0x2", "root": "0xr1"},
+				{"transactionHash": "0xt2", "gasUsed": "0x20", "effectiveGasPrice": "0x2", "root": "0xr2",
+					"contractAddress": "0xn"}
+			]`,
+		},
+		expectedBlock: &entity.Block{
+			Number:        big.NewInt(200),
+			Hash:          "0xh2",
+			ParentHash:    "0xh1",
+			Timestamp:     1711931392,
+			Miner:         "0xm",
+			BaseFeePerGas: big.NewInt(1),
+			Transactions: []*entity.Transaction{
+				{
+					Hash: "0xt1", From: "0x1", To: "0xc", Value: big.NewInt(100), Gas: big.NewInt(256), GasPrice: big.NewInt(3),
+					GasUsed: big.NewInt(16), EffectiveGasPrice: big.NewInt(2), Status: 1,
+				},
+				{
+					Hash: "0xt2", From: "0x2", ContractAddress: "0xn", Value: big.NewInt(0),
+					Gas: big.NewInt(256), GasPrice: big.NewInt(3),
+					GasUsed: big.NewInt(32), EffectiveGasPrice: big.NewInt(2), Status: 1,
+				},
+			},
+			Withdrawals: []*entity.Withdrawal{{Address: "0x5", Amount: big.NewInt(2_000_000_000)}},
+		},
+	},
+	{
+		name: "Block is not propagated yet",
+		responses: map[string]string{
+			"eth_getBlockByNumber": `"result": null`,
+		},
+		expectedBlock: nil,
+		expectedError: entity.ErrBlockNotFound,
+	},
+	{
+		name:    "Tracing is not supported",
+		options: []Option{Tracing(true)},
+		responses: map[string]string{
+			"eth_getBlockByNumber":     _testBlockResponse,
+			"eth_getBlockReceipts":     _testReceiptsResponse,
+			"debug_traceBlockByNumber": `"error": {"code": -32601, "message": "method not found"}`,
+		},
+		expectedBlock: nil,
+		expectedError: entity.ErrServiceResponse,
+	},
+}
+
+func Test_GetBlockNumberByTag(t *testing.T) {
+	for _, test := range testsGetBlockNumberByTag {
+		t.Run(test.name, func(t *testing.T) {
+			server := newFakeServer(t, test.responses)
+			defer server.Close()
+
+			w := newTestWebAPI(server.URL)
+
+			blockNumber, err := w.GetBlockNumberByTag(context.Background(), entity.AnchorFinalized)
+
+			assert.Equal(t, errors.Is(err, test.expectedError), true)
+			assert.Equal(t, toJSON(t, blockNumber), toJSON(t, test.expectedBlockNumber))
+		})
+	}
+}
+
+var testsGetBlockNumberByTag = []struct {
+	name                string
+	responses           map[string]string
+	expectedBlockNumber *big.Int
+	expectedError       error
+}{
+	{
+		name: "Success",
+		responses: map[string]string{
+			"eth_getBlockByNumber": `"result": {"number": "0xc8", "hash": "0xh2"}`,
+		},
+		expectedBlockNumber: big.NewInt(200),
+		expectedError:       nil,
+	},
+	{
+		name: "Tag is unknown yet",
+		responses: map[string]string{
+			"eth_getBlockByNumber": `"result": null`,
+		},
+		expectedBlockNumber: nil,
+		expectedError:       entity.ErrBlockNotFound,
+	},
+}
+
+func Test_GetBlockHeader(t *testing.T) {
+	server := newFakeServer(t, map[string]string{
+		"eth_getBlockByNumber": `"result": {"number": "0xc8", "hash": "0xh2", "parentHash": "0xh1",` +
+			`"timestamp": "0x660a0000"}`,
+	})
+	defer server.Close()
+
+	w := newTestWebAPI(server.URL)
+
+	header, err := w.GetBlockHeader(context.Background(), big.NewInt(200))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, toJSON(t, header), toJSON(t, &entity.BlockHeader{
+		Number: big.NewInt(200), Hash: "0xh2", ParentHash: "0xh1", Timestamp: 1711931392,
+	}))
+}
+
+func Test_GetBalance(t *testing.T) {
+	server := newFakeServer(t, map[string]string{
+		"eth_getBalance": `"result": "0x1bc16d674ec80000"`,
+		"eth_call":       `"result": "0x"`,
+	})
+	defer server.Close()
+
+	w := newTestWebAPI(server.URL)
+
+	balance, err := w.GetBalance(context.Background(), "0x1", big.NewInt(200))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, balance.String(), "2000000000000000000")
+	// Token which didn't exist at block has zero balance
+	balance, err = w.GetTokenBalance(context.Background(), "0xt", "0x1", big.NewInt(200))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, balance.String(), "0")
+}
+
+func Test_retryRequest(t *testing.T) {
+	for _, test := range testsRetryRequest {
+		t.Run(test.name, func(t *testing.T) {
+			var calls atomic.Int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				n := int(calls.Add(1)) - 1
+				if n >= len(test.bodies) {
+					n = len(test.bodies) - 1
+				}
+
+				_, _ = w.Write([]byte(test.bodies[n]))
+			}))
+			defer server.Close()
+
+			w := newTestWebAPI(server.URL, MaxRetries(3), TimeBetweenRetries(time.Millisecond))
+
+			body, err := blockNumberBuildRequestBody()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			response := blockNumberResponse{}
+			err = w.retryRequest(context.Background(), body, &response)
+
+			assert.Equal(t, errors.Is(err, test.expectedError), true)
+			assert.Equal(t, response.Result, test.expectedResult)
+			assert.Equal(t, int(calls.Load()), test.expectedCalls)
+		})
+	}
+}
+
+var testsRetryRequest = []struct {
+	name           string
+	bodies         []string
+	expectedResult string
+	expectedCalls  int
+	expectedError  error
+}{
+	{
+		name:           "Success",
+		bodies:         []string{`{"jsonrpc":"2.0","id":"getblock.io","result":"0xc8"}`},
+		expectedResult: "0xc8",
+		expectedCalls:  1,
+	},
+	{
+		name:           "Success after empty body",
+		bodies:         []string{``, `{"jsonrpc":"2.0","id":"getblock.io","result":"0xc8"}`},
+		expectedResult: "0xc8",
+		expectedCalls:  2,
+	},
+	{
+		name:          "Empty body on every retry",
+		bodies:        []string{``},
+		expectedCalls: 3,
+		expectedError: entity.ErrTooMuchRequestToService,
+	},
+	{
+		name:          "Error of service is not retried",
+		bodies:        []string{`{"jsonrpc":"2.0","id":"getblock.io","error":{"code":-32601,"message":"method not found"}}`},
+		expectedCalls: 1,
+		expectedError: entity.ErrServiceResponse,
+	},
+}
+
+func Test_decodeResponse(t *testing.T) {
+	for _, test := range testsDecodeResponse {
+		t.Run(test.name, func(t *testing.T) {
+			resp := &http.Response{Body: io.NopCloser(strings.NewReader(test.body))}
+
+			response := blockNumberResponse{}
+			err := decodeResponse(resp, &response)
+
+			assert.Equal(t, err != nil, test.isError)
+
+			if test.expectedError != nil {
+				assert.Equal(t, errors.Is(err, test.expectedError), true)
+			}
+			assert.Equal(t, response.Result, test.expectedResult)
+		})
+	}
+}
+
+var testsDecodeResponse = []struct {
+	name           string
+	body           string
+	expectedResult string
+	isError        bool
+	expectedError  error
+}{
+	{
+		name:           "Result",
+		body:           `{"result":"0x1"}`,
+		expectedResult: "0x1",
+	},
+	{
+		name:          "Empty body",
+		body:          ``,
+		isError:       true,
+		expectedError: io.EOF,
+	},
+	{
+		name:          "Error of service",
+		body:          `{"error":{"code":-32000,"message":"header not found"}}`,
+		isError:       true,
+		expectedError: entity.ErrServiceResponse,
+	},
+	{
+		name:    "Invalid json",
+		body:    `<html>`,
+		isError: true,
+	},
+}
+
+func Test_GetTokenTransfers(t *testing.T) {
+	var ranges []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params []struct {
+				FromBlock string `json:"fromBlock"`
+				ToBlock   string `json:"toBlock"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+
+		ranges = append(ranges, req.Params[0].FromBlock+"-"+req.Params[0].ToBlock)
+		// Token emits transfer without data in every part of range
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"getblock.io","result":[{"address":"0xt","topics":["` +
+			_transferTopic + `","0x0000000000000000000000000000000000000000000000000000000000000001",` +
+			`"0x0000000000000000000000000000000000000000000000000000000000000002"],"data":"0x",` +
+			`"blockNumber":"` + req.Params[0].FromBlock + `","transactionHash":"0xtx"}]}`))
+	}))
+	defer server.Close()
+
+	w := newTestWebAPI(server.URL, LogsBlockRange(100))
+
+	transfers, err := w.GetTokenTransfers(context.Background(), "0xt", big.NewInt(0), big.NewInt(250))
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, ranges, []string{"0x0-0x63", "0x64-0xc7", "0xc8-0xfa"})
+	assert.Equal(t, len(transfers), 3)
+	assert.Equal(t, transfers[2].Value.Sign(), 0)
+	assert.Equal(t, transfers[2].BlockNumber.Int64(), int64(200))
+}
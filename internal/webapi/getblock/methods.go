@@ -15,6 +15,8 @@ import (
 	"github.com/egor-denisov/biggest-change/internal/entity"
 )
 
+var _weiInGwei = big.NewInt(1_000_000_000)
+
 // Checking validity of url.
 func isValidURL(url string) bool {
 	return strings.HasPrefix(url, "https://go.getblock.io/")
@@ -26,12 +28,10 @@ func (w *StatsOfChangingWebAPI) retryRequest(
 	body *bytes.Buffer,
 	response interface{},
 ) error {
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, body)
-	if err != nil {
-		return fmt.Errorf("StatsOfChangingWebAPI - retryRequest: %w", err)
-	}
+	// Body is read by every request, so keeping its bytes for retries
+	data := body.Bytes()
 
-	request.Header.Set("Content-Type", "application/json")
+	var err error
 
 	for i := 0; i < w.maxRetries; i++ {
 		w.limiter.WaitForAvailability()
@@ -44,16 +44,26 @@ func (w *StatsOfChangingWebAPI) retryRequest(
 		default:
 		}
 
-		resp, err := w.client.Do(request)
-		if err != nil {
-			return fmt.Errorf("retryRequest - w.client.Do - retry %d: %w", i+1, err)
+		request, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(data))
+		if reqErr != nil {
+			return fmt.Errorf("StatsOfChangingWebAPI - retryRequest: %w", reqErr)
 		}
-		defer resp.Body.Close()
 
-		err = json.NewDecoder(resp.Body).Decode(response)
+		request.Header.Set("Content-Type", "application/json")
+
+		resp, doErr := w.client.Do(request)
+		if doErr != nil {
+			return fmt.Errorf("retryRequest - w.client.Do - retry %d: %w", i+1, doErr)
+		}
+
+		err = decodeResponse(resp, response)
 		if err == nil {
 			// If successful, return response
-			break
+			return nil
+		}
+		// Error of service won't disappear after retry
+		if errors.Is(err, entity.ErrServiceResponse) {
+			return fmt.Errorf("retryRequest - decodeResponse: %w", err)
 		}
 
 		// If empty body, trying again
@@ -67,6 +77,36 @@ func (w *StatsOfChangingWebAPI) retryRequest(
 	return fmt.Errorf("retryRequest: %w", err)
 }
 
+// Decoding body of response into response and checking error of json rpc.
+func decodeResponse(resp *http.Response, response interface{}) error {
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("decodeResponse - io.ReadAll: %w", err)
+	}
+
+	if len(data) == 0 {
+		return io.EOF
+	}
+
+	errResponse := errorResponse{}
+	if err := json.Unmarshal(data, &errResponse); err != nil {
+		return fmt.Errorf("decodeResponse - json.Unmarshal: %w", err)
+	}
+
+	if errResponse.Error != nil {
+		return fmt.Errorf("decodeResponse - %d %s: %w",
+			errResponse.Error.Code, errResponse.Error.Message, entity.ErrServiceResponse)
+	}
+
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("decodeResponse - json.Unmarshal: %w", err)
+	}
+
+	return nil
+}
+
 // Building Request Body for eth_getBlockByNumber request.
 func getBlockByNumberBuildRequestBody(blockNumber *big.Int) (*bytes.Buffer, error) {
 	data := request{
@@ -84,46 +124,72 @@ func getBlockByNumberBuildRequestBody(blockNumber *big.Int) (*bytes.Buffer, erro
 	return bytes.NewBuffer(jsonData), nil
 }
 
-// Making request and getting slice of transactions.
-func (w *StatsOfChangingWebAPI) getTransactionsByBlockNumber(
+// Making request and getting block with transactions.
+func (w *StatsOfChangingWebAPI) getBlockByNumber(
 	ctx context.Context,
 	blockNumber *big.Int,
-) ([]*entity.Transaction, error) {
+) (*entity.Block, error) {
 	body, err := getBlockByNumberBuildRequestBody(blockNumber)
 	if err != nil {
 		return nil,
-			fmt.Errorf("StatsOfChangingWebAPI - getTransactionsByBlockNumber - getBlockByNumberBuildRequestBody: %w", err)
+			fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - getBlockByNumberBuildRequestBody: %w", err)
 	}
 
 	response := getBlockByNumberResponse{}
 
 	if err := w.retryRequest(ctx, body, &response); err != nil {
 		return nil,
-			fmt.Errorf("StatsOfChangingWebAPI - getTransactionsByBlockNumber - w.retryRequest: %w", err)
+			fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - w.retryRequest: %w", err)
+	}
+	// Node behind load balancer returns null if head isn't propagated to it yet
+	if response.Result == nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - %s: %w", blockNumber, entity.ErrBlockNotFound)
 	}
 
-	res := make([]*entity.Transaction, len(response.Result.Transactions))
+	baseFeePerGas, err := hex2intOrNil(response.Result.BaseFeePerGas)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - hex2intOrNil: %w", err)
+	}
+
+	timestamp, err := hex2int(response.Result.Timestamp)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - hex2int: %w", err)
+	}
+
+	res := &entity.Block{
+		Number:        blockNumber,
+		Hash:          response.Result.Hash,
+		ParentHash:    response.Result.ParentHash,
+		Timestamp:     timestamp.Uint64(),
+		Miner:         response.Result.Miner,
+		BaseFeePerGas: baseFeePerGas,
+		Transactions:  make([]*entity.Transaction, len(response.Result.Transactions)),
+	}
 	// Сonverting the values from hex to *big.Int
 	for i, t := range response.Result.Transactions {
 		gas, err := hex2int(t.Gas)
 		if err != nil {
 			return nil,
-				fmt.Errorf("StatsOfChangingWebAPI - getTransactionsByBlockNumber - hex2int: %w", err)
+				fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - hex2int: %w", err)
 		}
 
 		gasPrice, err := hex2int(t.GasPrice)
 		if err != nil {
 			return nil,
-				fmt.Errorf("StatsOfChangingWebAPI - getTransactionsByBlockNumber - hex2int: %w", err)
+				fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - hex2int: %w", err)
 		}
 
 		value, err := hex2int(t.Value)
 		if err != nil {
 			return nil,
-				fmt.Errorf("StatsOfChangingWebAPI - getTransactionsByBlockNumber - hex2int: %w", err)
+				fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - hex2int: %w", err)
 		}
 
-		res[i] = &entity.Transaction{
+		res.Transactions[i] = &entity.Transaction{
+			Hash:     t.Hash,
 			From:     t.From,
 			To:       t.To,
 			Gas:      gas,
@@ -132,9 +198,191 @@ func (w *StatsOfChangingWebAPI) getTransactionsByBlockNumber(
 		}
 	}
 
+	// Amount of withdrawal is in gwei, so converting it to wei
+	for _, wd := range response.Result.Withdrawals {
+		amount, err := hex2int(wd.Amount)
+		if err != nil {
+			return nil,
+				fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - hex2int: %w", err)
+		}
+
+		res.Withdrawals = append(res.Withdrawals, &entity.Withdrawal{
+			Address: wd.Address,
+			Amount:  amount.Mul(amount, _weiInGwei),
+		})
+	}
+
+	if len(res.Transactions) == 0 {
+		return res, nil
+	}
+	// Transfers made by contracts are visible only in traces, which needs trace-capable endpoint
+	if w.tracing {
+		res.InternalTransfers, err = w.getInternalTransfers(ctx, blockNumber)
+		if err != nil {
+			return nil,
+				fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - w.getInternalTransfers: %w", err)
+		}
+	}
+
+	// Gas limit and gas price are not what sender really paid, so taking it from receipts
+	receipts, err := w.getReceiptsByBlockNumber(ctx, blockNumber)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - w.getReceiptsByBlockNumber: %w", err)
+	}
+
+	for i, t := range response.Result.Transactions {
+		receipt, ok := receipts[t.Hash]
+		if !ok {
+			return nil,
+				fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - receipt of %s: %w",
+					t.Hash, entity.ErrReceiptNotFound)
+		}
+
+		if err := applyReceipt(res.Transactions[i], receipt); err != nil {
+			return nil,
+				fmt.Errorf("StatsOfChangingWebAPI - getBlockByNumber - applyReceipt: %w", err)
+		}
+	}
+
 	return res, nil
 }
 
+// Building Request Body for eth_getBlockReceipts request.
+func getBlockReceiptsBuildRequestBody(blockNumber *big.Int) (*bytes.Buffer, error) {
+	data := request{
+		JSONRPC: "2.0",
+		Method:  "eth_getBlockReceipts",
+		Params:  []interface{}{int2hex(blockNumber)},
+		ID:      "getblock.io",
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("getBlockReceiptsBuildRequestBody - json.Marshal: %w", err)
+	}
+
+	return bytes.NewBuffer(jsonData), nil
+}
+
+// Making request and getting receipts of block by transaction hash.
+func (w *StatsOfChangingWebAPI) getReceiptsByBlockNumber(
+	ctx context.Context,
+	blockNumber *big.Int,
+) (map[string]*receiptResponse, error) {
+	body, err := getBlockReceiptsBuildRequestBody(blockNumber)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getReceiptsByBlockNumber - getBlockReceiptsBuildRequestBody: %w", err)
+	}
+
+	response := getBlockReceiptsResponse{}
+
+	if err := w.retryRequest(ctx, body, &response); err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getReceiptsByBlockNumber - w.retryRequest: %w", err)
+	}
+
+	res := make(map[string]*receiptResponse, len(response.Result))
+	for _, r := range response.Result {
+		res[r.TransactionHash] = r
+	}
+
+	return res, nil
+}
+
+// Filling transaction with gas used, effective gas price, status and created contract from receipt.
+func applyReceipt(t *entity.Transaction, r *receiptResponse) error {
+	gasUsed, err := hex2int(r.GasUsed)
+	if err != nil {
+		return fmt.Errorf("applyReceipt - hex2int: %w", err)
+	}
+
+	// Some clients don't return effective gas price for pre-London receipts, it's equal to gas price there
+	effectiveGasPrice := t.GasPrice
+	if r.EffectiveGasPrice != "" {
+		if effectiveGasPrice, err = hex2int(r.EffectiveGasPrice); err != nil {
+			return fmt.Errorf("applyReceipt - hex2int: %w", err)
+		}
+	}
+
+	// Pre-Byzantium receipts have state root instead of status, their transactions are treated as successful
+	status := big.NewInt(1)
+	if r.Status != "" {
+		if status, err = hex2int(r.Status); err != nil {
+			return fmt.Errorf("applyReceipt - hex2int: %w", err)
+		}
+	}
+
+	t.GasUsed = gasUsed
+	t.EffectiveGasPrice = effectiveGasPrice
+	t.Status = status.Uint64()
+	t.ContractAddress = r.ContractAddress
+
+	return nil
+}
+
+// Building Request Body for eth_getBlockByNumber request without transactions.
+// Block is described by hex number or by tag.
+func getBlockHeaderBuildRequestBody(block string) (*bytes.Buffer, error) {
+	data := request{
+		JSONRPC: "2.0",
+		Method:  "eth_getBlockByNumber",
+		Params:  []interface{}{block, false},
+		ID:      "getblock.io",
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("getBlockHeaderBuildRequestBody - json.Marshal: %w", err)
+	}
+
+	return bytes.NewBuffer(jsonData), nil
+}
+
+// Making request and getting header of block described by hex number or by tag.
+func (w *StatsOfChangingWebAPI) getBlockHeader(
+	ctx context.Context,
+	block string,
+) (*entity.BlockHeader, error) {
+	body, err := getBlockHeaderBuildRequestBody(block)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBlockHeader - getBlockHeaderBuildRequestBody: %w", err)
+	}
+
+	response := getBlockHeaderResponse{}
+
+	if err := w.retryRequest(ctx, body, &response); err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBlockHeader - w.retryRequest: %w", err)
+	}
+	// Node returns null if it doesn't know such block yet
+	if response.Result == nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBlockHeader - %s: %w", block, entity.ErrBlockNotFound)
+	}
+
+	number, err := hex2int(response.Result.Number)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBlockHeader - hex2int: %w", err)
+	}
+
+	timestamp, err := hex2int(response.Result.Timestamp)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getBlockHeader - hex2int: %w", err)
+	}
+
+	return &entity.BlockHeader{
+		Number:     number,
+		Hash:       response.Result.Hash,
+		ParentHash: response.Result.ParentHash,
+		Timestamp:  timestamp.Uint64(),
+	}, nil
+}
+
 // Building Request Body for eth_blockNumber request.
 func blockNumberBuildRequestBody() (*bytes.Buffer, error) {
 	data := request{
@@ -192,6 +440,15 @@ func hex2int(s string) (*big.Int, error) {
 	return i, nil
 }
 
+// Converting hex to *big.Int, but keeping nil for missing value.
+func hex2intOrNil(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, nil //nolint:nilnil // missing value isn't an error
+	}
+
+	return hex2int(s)
+}
+
 func int2hex(i *big.Int) string {
 	return fmt.Sprintf("%#x", i)
 }
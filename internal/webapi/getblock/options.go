@@ -35,3 +35,16 @@ func TimeBetweenRetries(timeBetweenRetries time.Duration) Option {
 		s.timeBetweenRetries = timeBetweenRetries
 	}
 }
+
+// Count of blocks in one eth_getLogs request, range isn't split if it's zero.
+func LogsBlockRange(logsBlockRange uint64) Option {
+	return func(s *StatsOfChangingWebAPI) {
+		s.logsBlockRange = logsBlockRange
+	}
+}
+
+func Tracing(tracing bool) Option {
+	return func(s *StatsOfChangingWebAPI) {
+		s.tracing = tracing
+	}
+}
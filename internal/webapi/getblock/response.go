@@ -7,7 +7,15 @@ type request struct {
 	Params  []interface{} `json:"params"`
 }
 
+type errorResponse struct {
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
 type transactionResponse struct {
+	Hash     string `json:"hash"`
 	From     string `json:"from"`
 	Gas      string `json:"gas"`
 	GasPrice string `json:"gasPrice"`
@@ -15,12 +23,76 @@ type transactionResponse struct {
 	Value    string `json:"value"`
 }
 
+type withdrawalResponse struct {
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+}
+
 type getBlockByNumberResponse struct {
-	Result struct {
-		Transactions []*transactionResponse `json:"transactions"`
+	Result *struct {
+		Hash          string                 `json:"hash"`
+		ParentHash    string                 `json:"parentHash"`
+		Timestamp     string                 `json:"timestamp"`
+		Miner         string                 `json:"miner"`
+		BaseFeePerGas string                 `json:"baseFeePerGas"`
+		Transactions  []*transactionResponse `json:"transactions"`
+		Withdrawals   []*withdrawalResponse  `json:"withdrawals"`
+	} `json:"result"`
+}
+
+type getBlockHeaderResponse struct {
+	Result *struct {
+		Number     string `json:"number"`
+		Hash       string `json:"hash"`
+		ParentHash string `json:"parentHash"`
+		Timestamp  string `json:"timestamp"`
 	} `json:"result"`
 }
 
 type blockNumberResponse struct {
 	Result string `json:"result"`
 }
+
+type receiptResponse struct {
+	TransactionHash   string `json:"transactionHash"`
+	GasUsed           string `json:"gasUsed"`
+	EffectiveGasPrice string `json:"effectiveGasPrice"`
+	Status            string `json:"status"`
+	ContractAddress   string `json:"contractAddress"`
+}
+
+type getBlockReceiptsResponse struct {
+	Result []*receiptResponse `json:"result"`
+}
+
+type logResponse struct {
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	BlockNumber     string   `json:"blockNumber"`
+	TransactionHash string   `json:"transactionHash"`
+	Removed         bool     `json:"removed"`
+}
+
+type getLogsResponse struct {
+	Result []*logResponse `json:"result"`
+}
+
+type callResponse struct {
+	Result string `json:"result"`
+}
+
+type callFrameResponse struct {
+	Type  string               `json:"type"`
+	From  string               `json:"from"`
+	To    string               `json:"to"`
+	Value string               `json:"value"`
+	Error string               `json:"error"`
+	Calls []*callFrameResponse `json:"calls"`
+}
+
+type traceBlockResponse struct {
+	Result []struct {
+		Result *callFrameResponse `json:"result"`
+	} `json:"result"`
+}
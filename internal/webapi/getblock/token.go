@@ -0,0 +1,163 @@
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+const (
+	// Keccak-256 of Transfer(address,address,uint256).
+	_transferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	// Selector of decimals().
+	_decimalsSelector = "0x313ce567"
+	// Indexed address is padded to 32 bytes, the address itself is last 20 bytes.
+	_addressInTopicOffset = 26
+	// Transfer of ERC-20 has signature and two indexed addresses as topics.
+	_erc20TransferTopicsCount = 3
+)
+
+// Building Request Body for eth_getLogs request with ERC-20 Transfer topic.
+func getTransferLogsBuildRequestBody(token string, fromBlock, toBlock *big.Int) (*bytes.Buffer, error) {
+	data := request{
+		JSONRPC: "2.0",
+		Method:  "eth_getLogs",
+		Params: []interface{}{map[string]interface{}{
+			"address":   token,
+			"fromBlock": int2hex(fromBlock),
+			"toBlock":   int2hex(toBlock),
+			"topics":    []string{_transferTopic},
+		}},
+		ID: "getblock.io",
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("getTransferLogsBuildRequestBody - json.Marshal: %w", err)
+	}
+
+	return bytes.NewBuffer(jsonData), nil
+}
+
+// Making request and getting transfers of token in range of blocks.
+func (w *StatsOfChangingWebAPI) getTokenTransfers(
+	ctx context.Context,
+	token string,
+	fromBlock, toBlock *big.Int,
+) ([]*entity.TokenTransfer, error) {
+	body, err := getTransferLogsBuildRequestBody(token, fromBlock, toBlock)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getTokenTransfers - getTransferLogsBuildRequestBody: %w", err)
+	}
+
+	response := getLogsResponse{}
+
+	if err := w.retryRequest(ctx, body, &response); err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getTokenTransfers - w.retryRequest: %w", err)
+	}
+
+	res := make([]*entity.TokenTransfer, 0, len(response.Result))
+
+	for _, l := range response.Result {
+		// Skipping logs of ERC-721 transfers (token id is indexed) and logs removed by reorg
+		if l.Removed || len(l.Topics) != _erc20TransferTopicsCount {
+			continue
+		}
+
+		// Non-standard tokens emit transfers without data, their value is counted as zero
+		value := new(big.Int)
+		if l.Data != "0x" {
+			if value, err = hex2int(l.Data); err != nil {
+				return nil,
+					fmt.Errorf("StatsOfChangingWebAPI - getTokenTransfers - hex2int: %w", err)
+			}
+		}
+
+		blockNumber, err := hex2int(l.BlockNumber)
+		if err != nil {
+			return nil,
+				fmt.Errorf("StatsOfChangingWebAPI - getTokenTransfers - hex2int: %w", err)
+		}
+
+		res = append(res, &entity.TokenTransfer{
+			Token:       l.Address,
+			From:        topic2address(l.Topics[1]),
+			To:          topic2address(l.Topics[2]),
+			Value:       value,
+			BlockNumber: blockNumber,
+			TxHash:      l.TransactionHash,
+		})
+	}
+
+	return res, nil
+}
+
+// Building Request Body for eth_call request of decimals().
+func decimalsBuildRequestBody(token string) (*bytes.Buffer, error) {
+	data := request{
+		JSONRPC: "2.0",
+		Method:  "eth_call",
+		Params: []interface{}{
+			map[string]string{"to": token, "data": _decimalsSelector},
+			"latest",
+		},
+		ID: "getblock.io",
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("decimalsBuildRequestBody - json.Marshal: %w", err)
+	}
+
+	return bytes.NewBuffer(jsonData), nil
+}
+
+// Making request and getting decimals of token.
+func (w *StatsOfChangingWebAPI) getTokenDecimals(ctx context.Context, token string) (uint8, error) {
+	body, err := decimalsBuildRequestBody(token)
+	if err != nil {
+		return 0,
+			fmt.Errorf("StatsOfChangingWebAPI - getTokenDecimals - decimalsBuildRequestBody: %w", err)
+	}
+
+	response := callResponse{}
+
+	if err := w.retryRequest(ctx, body, &response); err != nil {
+		return 0,
+			fmt.Errorf("StatsOfChangingWebAPI - getTokenDecimals - w.retryRequest: %w", err)
+	}
+	// Contract without decimals() returns empty result
+	if response.Result == "" || response.Result == "0x" {
+		return 0,
+			fmt.Errorf("StatsOfChangingWebAPI - getTokenDecimals - %s: %w", token, entity.ErrNotERC20Token)
+	}
+
+	decimals, err := hex2int(response.Result)
+	if err != nil {
+		return 0,
+			fmt.Errorf("StatsOfChangingWebAPI - getTokenDecimals - hex2int: %w", err)
+	}
+
+	if !decimals.IsUint64() || decimals.Uint64() > math.MaxUint8 {
+		return 0,
+			fmt.Errorf("StatsOfChangingWebAPI - getTokenDecimals - %s: %w", token, entity.ErrNotERC20Token)
+	}
+
+	return uint8(decimals.Uint64()), nil
+}
+
+// Getting address from indexed topic.
+func topic2address(topic string) string {
+	if len(topic) < _addressInTopicOffset {
+		return topic
+	}
+
+	return "0x" + topic[_addressInTopicOffset:]
+}
@@ -0,0 +1,107 @@
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/egor-denisov/biggest-change/internal/entity"
+)
+
+// Types of call frames which can move ether.
+// DELEGATECALL and STATICCALL can't, but callTracer reports value of parent frame for DELEGATECALL.
+var _valueCallTypes = map[string]struct{}{
+	"CALL":         {},
+	"CALLCODE":     {},
+	"CREATE":       {},
+	"CREATE2":      {},
+	"SELFDESTRUCT": {},
+}
+
+// Building Request Body for debug_traceBlockByNumber request with callTracer.
+func traceBlockBuildRequestBody(blockNumber *big.Int) (*bytes.Buffer, error) {
+	data := request{
+		JSONRPC: "2.0",
+		Method:  "debug_traceBlockByNumber",
+		Params:  []interface{}{int2hex(blockNumber), map[string]string{"tracer": "callTracer"}},
+		ID:      "getblock.io",
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("traceBlockBuildRequestBody - json.Marshal: %w", err)
+	}
+
+	return bytes.NewBuffer(jsonData), nil
+}
+
+// Making request and getting ether transfers made by contracts in block.
+func (w *StatsOfChangingWebAPI) getInternalTransfers(
+	ctx context.Context,
+	blockNumber *big.Int,
+) ([]*entity.InternalTransfer, error) {
+	body, err := traceBlockBuildRequestBody(blockNumber)
+	if err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getInternalTransfers - traceBlockBuildRequestBody: %w", err)
+	}
+
+	response := traceBlockResponse{}
+
+	if err := w.retryRequest(ctx, body, &response); err != nil {
+		return nil,
+			fmt.Errorf("StatsOfChangingWebAPI - getInternalTransfers - w.retryRequest: %w", err)
+	}
+
+	var res []*entity.InternalTransfer
+
+	// Top frame is transaction itself and is already counted, so only its calls are flattened
+	for _, tx := range response.Result {
+		if tx.Result == nil || tx.Result.Error != "" {
+			continue
+		}
+
+		for _, call := range tx.Result.Calls {
+			if res, err = flattenCallFrame(call, res); err != nil {
+				return nil,
+					fmt.Errorf("StatsOfChangingWebAPI - getInternalTransfers - flattenCallFrame: %w", err)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// Adding transfers of frame and its subcalls to res.
+// Reverted frame doesn't move anything, including its subcalls.
+func flattenCallFrame(frame *callFrameResponse, res []*entity.InternalTransfer) ([]*entity.InternalTransfer, error) {
+	if frame.Error != "" {
+		return res, nil
+	}
+
+	if _, ok := _valueCallTypes[frame.Type]; ok {
+		value, err := hex2int(frame.Value)
+		if err != nil {
+			return nil, fmt.Errorf("flattenCallFrame - hex2int: %w", err)
+		}
+
+		if value.Sign() > 0 {
+			res = append(res, &entity.InternalTransfer{
+				From:  frame.From,
+				To:    frame.To,
+				Value: value,
+			})
+		}
+	}
+
+	for _, call := range frame.Calls {
+		var err error
+		if res, err = flattenCallFrame(call, res); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}